@@ -0,0 +1,98 @@
+package sqlbuilder
+
+import "fmt"
+
+type aggregate struct {
+	fn       string
+	col      string
+	distinct bool
+	filter   *where
+}
+
+// Count returns a new statement with COUNT(*) selected and scanned into 'dest'.
+// Use As to set an alias and Filter to restrict the rows that are counted.
+func (s SelectStatement) Count(dest interface{}) SelectStatement {
+	return s.addAggregate(aggregate{fn: "COUNT", col: "*"}, dest)
+}
+
+// CountDistinct returns a new statement with COUNT(DISTINCT col) selected and scanned into 'dest'.
+func (s SelectStatement) CountDistinct(col string, dest interface{}) SelectStatement {
+	return s.addAggregate(aggregate{fn: "COUNT", col: col, distinct: true}, dest)
+}
+
+// Sum returns a new statement with SUM(col) selected and scanned into 'dest'.
+func (s SelectStatement) Sum(col string, dest interface{}) SelectStatement {
+	return s.addAggregate(aggregate{fn: "SUM", col: col}, dest)
+}
+
+// Avg returns a new statement with AVG(col) selected and scanned into 'dest'.
+func (s SelectStatement) Avg(col string, dest interface{}) SelectStatement {
+	return s.addAggregate(aggregate{fn: "AVG", col: col}, dest)
+}
+
+// Min returns a new statement with MIN(col) selected and scanned into 'dest'.
+func (s SelectStatement) Min(col string, dest interface{}) SelectStatement {
+	return s.addAggregate(aggregate{fn: "MIN", col: col}, dest)
+}
+
+// Max returns a new statement with MAX(col) selected and scanned into 'dest'.
+func (s SelectStatement) Max(col string, dest interface{}) SelectStatement {
+	return s.addAggregate(aggregate{fn: "MAX", col: col}, dest)
+}
+
+// Filter modifies the preceding aggregate column so that only rows matching the condition
+// 'cond' are aggregated. For example Sum("total", &paid).Filter("status = ?", "paid")
+//
+// This is rendered as FILTER (WHERE ...) where the dialect has FeatureFilter, and as
+// CASE WHEN ... END otherwise.
+// This panics if the preceding column is not an aggregate.
+func (s SelectStatement) Filter(cond string, args ...interface{}) SelectStatement {
+	i := len(s.columns) - 1
+	if i < 0 || s.columns[i].agg == nil {
+		panic("sqlbuilder: Filter without a preceding aggregate column")
+	}
+	c := s.columns[i]
+	a := *c.agg
+	a.filter = &where{"", cond, args}
+	c.agg = &a
+	s.columns = append(s.columns[:i:i], c)
+	return s
+}
+
+func (s SelectStatement) addAggregate(a aggregate, dest interface{}) SelectStatement {
+	if dest == nil {
+		dest = nullDest
+	}
+	s.columns = append(s.columns, column{dest: dest, agg: &a})
+	s.last = lastWasColumnName
+	return s
+}
+
+// build renders the aggregate function. Any arguments to its filter are appended to 'args'.
+func (a aggregate) build(args []interface{}, idx int, dialect Dialect) (string, []interface{}, int) {
+	col := a.col
+	if col != "*" {
+		col = quoteName(dialect, col)
+	}
+
+	distinct := ""
+	if a.distinct {
+		distinct = "DISTINCT "
+	}
+
+	if a.filter == nil {
+		return fmt.Sprintf("%s(%s%s)", a.fn, distinct, col), args, idx
+	}
+
+	var cond string
+	cond, args, idx = a.filter.build(args, idx, dialect)
+
+	if dialect.Features().Has(FeatureFilter) {
+		return fmt.Sprintf("%s(%s%s) FILTER (WHERE %s)", a.fn, distinct, col, cond), args, idx
+	}
+
+	if col == "*" {
+		col = "1"
+	}
+	return fmt.Sprintf("%s(%sCASE WHEN %s THEN %s END)", a.fn, distinct, cond, col), args, idx
+}
@@ -0,0 +1,104 @@
+package sqlbuilder
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+// namedDialect is implemented by dialects that can bind parameters by name.
+type namedDialect interface {
+	// namePrefix returns the prefix of named placeholders, e.g. "@", or "" if the
+	// dialect binds parameters by position.
+	namePrefix() string
+}
+
+// bind returns the placeholder for argument 'arg', which is appended to 'args'.
+//
+// If the dialect binds parameters by name, the argument is passed as an sql.NamedArg.
+// Its name is taken from 'hint' (usually a column name), made unique among the arguments
+// so far, unless the caller supplied an sql.NamedArg, whose name is kept.
+func bind(dialect Dialect, args []interface{}, idx int, hint string, arg interface{}) (string, []interface{}, int) {
+	prefix := ""
+	if nd, ok := dialect.(namedDialect); ok {
+		prefix = nd.namePrefix()
+	}
+	if prefix == "" {
+		return dialect.Placeholder(idx), append(args, arg), idx + 1
+	}
+
+	na, ok := arg.(sql.NamedArg)
+	if !ok {
+		na = sql.Named(uniqueParamName(args, sanitiseParamName(hint, idx)), arg)
+	}
+	return prefix + na.Name, append(args, na), idx + 1
+}
+
+// sanitiseParamName makes a parameter name from a column name such as "c.id", using only
+// its last part. Any character that is not a letter, digit or underscore is replaced.
+func sanitiseParamName(hint string, idx int) string {
+	if i := strings.LastIndexByte(hint, '.'); i >= 0 {
+		hint = hint[i+1:]
+	}
+	hint = strings.Trim(hint, "`\"[]")
+
+	b := []byte(hint)
+	for i, c := range b {
+		if !isIdentifierByte(c) {
+			b[i] = '_'
+		}
+	}
+
+	if len(b) == 0 || ('0' <= b[0] && b[0] <= '9') {
+		return "p" + strconv.Itoa(idx+1)
+	}
+	return string(b)
+}
+
+// uniqueParamName appends a suffix to 'name' if needed so that it differs from the names
+// of the arguments so far, including those of named markers. Like SQL identifiers, names
+// are compared case-insensitively.
+func uniqueParamName(args []interface{}, name string) string {
+	taken := func(n string) bool {
+		for _, arg := range args {
+			switch a := arg.(type) {
+			case sql.NamedArg:
+				if strings.EqualFold(a.Name, n) {
+					return true
+				}
+			case boundParam:
+				if strings.EqualFold(a.bound, n) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	unique := name
+	for i := 2; taken(unique); i++ {
+		unique = name + "_" + strconv.Itoa(i)
+	}
+	return unique
+}
+
+// comparedColumn finds the column compared with the parameter following the SQL text
+// 'before', e.g. "status" in "status = ", or "" if there is none.
+func comparedColumn(before string) string {
+	s := strings.TrimRight(before, " ")
+	op := strings.TrimRight(s, "=<>!")
+	if op == s {
+		return ""
+	}
+	s = strings.TrimRight(op, " ")
+
+	i := len(s)
+	for i > 0 && (isIdentifierByte(s[i-1]) || s[i-1] == '.') {
+		i--
+	}
+	return s[i:]
+}
+
+func isIdentifierByte(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
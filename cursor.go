@@ -0,0 +1,119 @@
+package sqlbuilder
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/gob"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrInvalidCursor is returned when a cursor is malformed or its signature is wrong.
+	ErrInvalidCursor = errors.New("sqlbuilder: invalid cursor")
+
+	// ErrCursorMismatch is returned when a cursor was made for a different ordering.
+	ErrCursorMismatch = errors.New("sqlbuilder: cursor does not match the ORDER BY columns")
+)
+
+func init() {
+	gob.Register(time.Time{})
+}
+
+// CursorCodec encodes and decodes opaque pagination cursors. A cursor holds the ORDER BY
+// values of the last row of a page; it is signed with a secret key so that clients cannot
+// tamper with it, although they can read it.
+//
+// The values are encoded with encoding/gob. The basic types and time.Time are supported;
+// any other types must be registered with gob.Register.
+type CursorCodec struct {
+	key []byte
+}
+
+type cursor struct {
+	Order  []string
+	Values []interface{}
+}
+
+// NewCursorCodec returns a new codec that signs cursors using 'key'.
+func NewCursorCodec(key []byte) CursorCodec {
+	return CursorCodec{key}
+}
+
+// Encode returns a cursor for the page following the row whose ORDER BY values were 'last'.
+// The values must correspond one-to-one with the ORDER BY columns of 's'.
+func (c CursorCodec) Encode(s SelectStatement, last ...interface{}) (string, error) {
+	if len(last) != len(s.order) {
+		return "", ErrCursorMismatch
+	}
+
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(cursor{cursorOrder(s), last})
+	if err != nil {
+		return "", err
+	}
+
+	payload := buf.Bytes()
+	return base64.RawURLEncoding.EncodeToString(append(payload, c.sign(payload)...)), nil
+}
+
+// SeekAfter decodes 'token' and returns a new statement that fetches the page of up to
+// 'limit' rows following the one it was made from; see SelectStatement.SeekAfter.
+// An empty token fetches the first page.
+//
+// Tampered tokens give ErrInvalidCursor and tokens made for a different ORDER BY
+// give ErrCursorMismatch.
+func (c CursorCodec) SeekAfter(s SelectStatement, limit int, token string) (SelectStatement, error) {
+	if token == "" {
+		return s.SeekAfter(limit), nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) < sha256.Size {
+		return s, ErrInvalidCursor
+	}
+
+	payload, sig := raw[:len(raw)-sha256.Size], raw[len(raw)-sha256.Size:]
+	if !hmac.Equal(sig, c.sign(payload)) {
+		return s, ErrInvalidCursor
+	}
+
+	var cur cursor
+	err = gob.NewDecoder(bytes.NewReader(payload)).Decode(&cur)
+	if err != nil {
+		return s, ErrInvalidCursor
+	}
+
+	order := cursorOrder(s)
+	if len(cur.Order) != len(order) || len(cur.Values) != len(order) {
+		return s, ErrCursorMismatch
+	}
+	for i := range order {
+		if cur.Order[i] != order[i] {
+			return s, ErrCursorMismatch
+		}
+	}
+
+	return s.SeekAfter(limit, cur.Values...), nil
+}
+
+func (c CursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// cursorOrder identifies the ordering of a statement, so that cursors cannot be used
+// with a statement ordered differently.
+func cursorOrder(s SelectStatement) []string {
+	order := make([]string, len(s.order))
+	for i, o := range s.order {
+		order[i] = o.col
+		if o.desc {
+			order[i] += " DESC"
+		}
+	}
+	return order
+}
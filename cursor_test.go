@@ -0,0 +1,98 @@
+package sqlbuilder
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	codec := NewCursorCodec([]byte("secret"))
+	created := time.Date(2017, 11, 5, 10, 0, 0, 0, time.UTC)
+
+	page := Select().
+		Dialect(Postgres).
+		From("orders").
+		Columns("id", "created").
+		OrderBy("created").Desc().OrderBy("id")
+
+	token, err := codec.Encode(page, created, 42)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	next, err := codec.SeekAfter(page, 10, token)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	query, args, _ := next.Build()
+
+	expectedQuery := `SELECT id, created
+ FROM orders
+ WHERE ((created < $1) OR (created = $2 AND id > $3))
+ ORDER BY created DESC, id
+ LIMIT 10`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{created, created, 42}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestCursorFirstPage(t *testing.T) {
+	codec := NewCursorCodec([]byte("secret"))
+	page := Select().From("orders").Columns("id").OrderBy("id")
+
+	first, err := codec.SeekAfter(page, 10, "")
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	query, _, _ := first.Build()
+	expectedQuery := "SELECT id\n FROM orders\n ORDER BY id\n LIMIT 10"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+}
+
+func TestCursorRejected(t *testing.T) {
+	codec := NewCursorCodec([]byte("secret"))
+	page := Select().From("orders").Columns("id").OrderBy("id")
+
+	token, err := codec.Encode(page, 42)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	tampered := []byte(token)
+	tampered[2] ^= 1
+
+	cases := []struct {
+		codec CursorCodec
+		page  SelectStatement
+		token string
+		err   error
+	}{
+		{codec, page, string(tampered), ErrInvalidCursor},
+		{codec, page, "not base64!", ErrInvalidCursor},
+		{NewCursorCodec([]byte("other")), page, token, ErrInvalidCursor},
+		{codec, page.Desc(), token, ErrCursorMismatch},
+		{codec, page.OrderBy("name"), token, ErrCursorMismatch},
+	}
+
+	for i, c := range cases {
+		_, err := c.codec.SeekAfter(c.page, 10, c.token)
+		if err != c.err {
+			t.Errorf("%d: got %v, want %v", i, err, c.err)
+		}
+	}
+
+	_, err = codec.Encode(page, 1, 2)
+	if err != ErrCursorMismatch {
+		t.Errorf("got %v, want %v", err, ErrCursorMismatch)
+	}
+}
@@ -13,11 +13,14 @@ func Delete() DeleteStatement {
 
 // DeleteStatement represents an DELETE statement.
 type DeleteStatement struct {
-	dialect Dialect
-	last    lastWas
-	table   name
-	wheres  []where
-	args    []interface{}
+	dialect  Dialect
+	schema   string
+	resolver TableResolver
+	last     lastWas
+	table    name
+	wheres   []where
+	args     []interface{}
+	params   params
 }
 
 // Dialect returns a new statement with dialect set to 'dialect'.
@@ -26,9 +29,16 @@ func (s DeleteStatement) Dialect(dialect Dialect) DeleteStatement {
 	return s
 }
 
+// Schema returns a new statement in which table names that have no schema are qualified
+// by 'schema'. This overrides any default schema of the dialect.
+func (s DeleteStatement) Schema(schema string) DeleteStatement {
+	s.schema = schema
+	return s
+}
+
 // From returns a new statement with the table to delete set to 'table'.
 func (s DeleteStatement) From(table string) DeleteStatement {
-	s.table = name{table, ""}
+	s.table = tableName(table)
 	s.last = lastWasTableName
 	return s
 }
@@ -37,7 +47,7 @@ func (s DeleteStatement) From(table string) DeleteStatement {
 func (s DeleteStatement) As(alias string) DeleteStatement {
 	switch s.last {
 	case lastWasTableName:
-		s.table = name{s.table.name, alias}
+		s.table.alias = alias
 		//case lastWasColumnName:
 		//	i := len(s.selects) - 1
 		//	sel := s.selects[i]
@@ -72,9 +82,15 @@ func (s DeleteStatement) Build() (query string, args []interface{}) {
 		panic("sqlbuilder: DELETE with no where clauses")
 	}
 
-	query = "DELETE FROM " + s.table.String()
+	refs := make(tableRefs)
+	table := refs.resolve(s.table.qualify(s.schema, s.dialect), resolverOrDefault(s.resolver))
+	query = "DELETE FROM " + table.QuotedAs(s.dialect)
+	if table.alias != "" && isSQLServer(s.dialect) {
+		query = "DELETE " + table.alias + " FROM " + table.QuotedAs(s.dialect)
+	}
 
-	query, args, _ = buildWhereClause(query, args, 0, s.wheres, s.dialect)
+	query, args, _ = buildWhereClause(query, args, 0, refs.conditions(bindParams(s.wheres, s.params)), s.dialect)
+	args = unwrapParams(args)
 
 	return
 }
@@ -40,3 +40,52 @@ func TestDeleteWithWherePostgres(t *testing.T) {
 		t.Errorf("bad args: %v", args)
 	}
 }
+
+func TestDeleteWithWhereSQLServer(t *testing.T) {
+	query, args := Delete().
+		Dialect(SQLServer).
+		From("customers").As("c").
+		Where("c.id", "= ?", 9).
+		Build()
+
+	expectedQuery := "DELETE c FROM customers AS c\n WHERE (c.id = @p1)"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{9}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestDeleteWithWhereOracle(t *testing.T) {
+	query, args := Delete().
+		Dialect(Oracle).
+		From("customers").As("c").
+		Where("c.id", "= ?", 9).
+		Build()
+
+	expectedQuery := "DELETE FROM customers c\n WHERE (c.id = :1)"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{9}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestDeleteWithSchema(t *testing.T) {
+	query, _ := Delete().
+		Dialect(SQLServer.Schema("dbo")).
+		From("customers").As("c").
+		Where("c.id", "= ?", 9).
+		Build()
+
+	expectedQuery := "DELETE c FROM dbo.customers AS c\n WHERE (c.id = @p1)"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+}
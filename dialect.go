@@ -2,30 +2,194 @@ package sqlbuilder
 
 import (
 	"strconv"
+	"strings"
 )
 
 // Dialect represents a SQL dialect.
 type Dialect interface {
 	// Placeholder returns the placeholder binding string for parameter at index idx.
 	Placeholder(idx int) string
+
+	// Quote returns the identifier quoted so that any character in it may be used.
+	Quote(identifier string) string
+
+	// Paginate adds the row-limiting clauses described by 'p' to a SELECT query, which
+	// always starts with "SELECT " and has no locking clause yet.
+	Paginate(query string, p Pagination) string
+
+	// Features returns the set of optional features that the dialect supports.
+	Features() Features
+}
+
+// Pagination describes which rows of its result a SELECT statement returns.
+type Pagination struct {
+	Limit    *int // the maximum number of rows, or nil for no limit
+	Offset   *int // the number of rows skipped, or nil for none
+	WithTies bool // include any further rows that tie with the last row
+	Ordered  bool // whether the query has an ORDER BY clause
 }
 
-type MySQLDialect struct{}
-type PostgresDialect struct{}
+// MySQLDialect is the dialect for MySQL. By default, it targets the latest release;
+// use Version for earlier releases.
+type MySQLDialect struct {
+	version version
+	schema  string
+}
+
+// PostgresDialect is the dialect for Postgres. By default, it targets the latest release;
+// use Version for earlier releases.
+type PostgresDialect struct {
+	version version
+	schema  string
+}
 
 var (
 	MySQL    MySQLDialect    // MySQL
-	SQLite   MySQLDialect    // SQLite (same as MySQL)
+	SQLite   SQLiteDialect   // SQLite
 	Postgres PostgresDialect // Postgres
 )
 
-var DefaultDialect = MySQL // Default dialect
+var DefaultDialect Dialect = MySQL // Default dialect
+
+var mySQLFeatures = NewFeatures(FeatureUpsert, FeatureReplace, FeatureRightJoin, FeatureNaturalJoin,
+	FeatureJoinUsing, FeatureLateral, FeatureWindowFunctions, FeatureRowValues, FeatureRollup,
+	FeatureRowLocking, FeatureLockShare, FeatureNoWait, FeatureSkipLocked, FeatureLockOf, FeatureCTE)
+
+var postgresFeatures = NewFeatures(FeatureReturning, FeatureUpsert, FeatureRightJoin, FeatureFullOuterJoin,
+	FeatureNaturalJoin, FeatureJoinUsing, FeatureLateral, FeatureDistinctOn, FeatureWindowFunctions,
+	FeatureFilter, FeatureNullsOrdering, FeatureRowValues, FeatureRollup, FeatureCube, FeatureGroupingSets,
+	FeatureWithTies, FeatureRowLocking, FeatureLockShare, FeatureKeyLocks, FeatureNoWait, FeatureSkipLocked,
+	FeatureLockOf, FeatureCTE, FeatureMerge)
+
+// Version returns the dialect for a particular release of MySQL, e.g. MySQL.Version(8, 0, 20).
+func (dialect MySQLDialect) Version(v ...int) MySQLDialect {
+	dialect.version = newVersion(v)
+	return dialect
+}
+
+// Schema returns the dialect with a default database for table names that have none,
+// unless the statement sets its own with Schema.
+func (dialect MySQLDialect) Schema(schema string) MySQLDialect {
+	dialect.schema = schema
+	return dialect
+}
+
+func (dialect MySQLDialect) defaultSchema() string {
+	return dialect.schema
+}
 
 func (dialect MySQLDialect) Placeholder(idx int) string {
 	return "?"
 }
 
+func (dialect MySQLDialect) Quote(identifier string) string {
+	return "`" + strings.Replace(identifier, "`", "``", -1) + "`"
+}
+
+// Features returns the features of the dialect. Before 8.0, MySQL lacked window functions,
+// common table expressions, NOWAIT, SKIP LOCKED and FOR ... OF; lateral joins need 8.0.14.
+func (dialect MySQLDialect) Features() Features {
+	fs := mySQLFeatures
+	if !dialect.version.atLeast(8, 0, 14) {
+		fs = fs.Without(FeatureLateral)
+	}
+	if !dialect.version.atLeast(8) {
+		fs = fs.Without(FeatureWindowFunctions, FeatureCTE, FeatureNoWait, FeatureSkipLocked, FeatureLockOf)
+	}
+	return fs
+}
+
+// Paginate uses LIMIT and OFFSET. MySQL does not allow OFFSET without LIMIT, so the
+// largest possible limit is used when there is only an offset.
+func (dialect MySQLDialect) Paginate(query string, p Pagination) string {
+	if p.WithTies {
+		panic("sqlbuilder: WITH TIES is not supported by MySQL")
+	}
+	if p.Limit != nil {
+		query += "\n LIMIT " + strconv.Itoa(*p.Limit)
+	} else if p.Offset != nil {
+		query += "\n LIMIT 18446744073709551615"
+	}
+	if p.Offset != nil {
+		query += "\n OFFSET " + strconv.Itoa(*p.Offset)
+	}
+	return query
+}
+
+// Version returns the dialect for a particular release of Postgres, e.g. Postgres.Version(12).
+func (dialect PostgresDialect) Version(v ...int) PostgresDialect {
+	dialect.version = newVersion(v)
+	return dialect
+}
+
+// Schema returns the dialect with a default schema for table names that have none,
+// unless the statement sets its own with Schema.
+func (dialect PostgresDialect) Schema(schema string) PostgresDialect {
+	dialect.schema = schema
+	return dialect
+}
+
+func (dialect PostgresDialect) defaultSchema() string {
+	return dialect.schema
+}
+
 func (dialect PostgresDialect) Placeholder(idx int) string {
 	return "$" + strconv.Itoa(idx+1)
 }
 
+func (dialect PostgresDialect) Quote(identifier string) string {
+	return `"` + strings.Replace(identifier, `"`, `""`, -1) + `"`
+}
+
+// Features returns the features of the dialect. WITH TIES needs Postgres 13 and MERGE
+// needs Postgres 15.
+func (dialect PostgresDialect) Features() Features {
+	fs := postgresFeatures
+	if !dialect.version.atLeast(13) {
+		fs = fs.Without(FeatureWithTies)
+	}
+	if !dialect.version.atLeast(15) {
+		fs = fs.Without(FeatureMerge)
+	}
+	return fs
+}
+
+// Paginate uses LIMIT and OFFSET, or OFFSET and FETCH FIRST when ties are wanted.
+func (dialect PostgresDialect) Paginate(query string, p Pagination) string {
+	if p.WithTies {
+		return query + fetchFirst(p, "FIRST")
+	}
+	if p.Limit != nil {
+		query += "\n LIMIT " + strconv.Itoa(*p.Limit)
+	}
+	if p.Offset != nil {
+		query += "\n OFFSET " + strconv.Itoa(*p.Offset)
+	}
+	return query
+}
+
+// fetchFirst renders standard SQL pagination, i.e. OFFSET m ROWS FETCH FIRST n ROWS ONLY.
+// 'first' is either FIRST or NEXT, which are equivalent.
+func fetchFirst(p Pagination, first string) string {
+	sql := ""
+	if p.Offset != nil {
+		sql += "\n OFFSET " + strconv.Itoa(*p.Offset) + " ROWS"
+	}
+	if p.Limit != nil {
+		sql += "\n FETCH " + first + " " + strconv.Itoa(*p.Limit) + " ROWS"
+		if p.WithTies {
+			if !p.Ordered {
+				panic("sqlbuilder: WITH TIES requires ORDER BY")
+			}
+			sql += " WITH TIES"
+		} else {
+			sql += " ONLY"
+		}
+	}
+	return sql
+}
+
+func isMySQL(dialect Dialect) bool {
+	_, ok := dialect.(MySQLDialect)
+	return ok
+}
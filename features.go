@@ -0,0 +1,132 @@
+package sqlbuilder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Feature is an optional SQL feature, which some dialects support and others do not.
+type Feature uint64
+
+const (
+	FeatureReturning       Feature = 1 << iota // INSERT ... RETURNING, or its equivalent
+	FeatureUpsert                              // INSERT ... ON CONFLICT DO UPDATE, or its equivalent
+	FeatureReplace                             // INSERT OR REPLACE, or REPLACE
+	FeatureRightJoin                           // RIGHT [OUTER] JOIN
+	FeatureFullOuterJoin                       // FULL OUTER JOIN
+	FeatureNaturalJoin                         // NATURAL JOIN
+	FeatureJoinUsing                           // JOIN ... USING (...)
+	FeatureLateral                             // JOIN LATERAL, or CROSS/OUTER APPLY
+	FeatureDistinctOn                          // SELECT DISTINCT ON (...)
+	FeatureWindowFunctions                     // window functions and named windows
+	FeatureFilter                              // aggregate FILTER (WHERE ...)
+	FeatureNullsOrdering                       // ORDER BY ... NULLS FIRST/LAST
+	FeatureRowValues                           // row-value comparisons such as (a, b) > (?, ?)
+	FeatureRollup                              // GROUP BY ROLLUP, or WITH ROLLUP
+	FeatureCube                                // GROUP BY CUBE
+	FeatureGroupingSets                        // GROUP BY GROUPING SETS
+	FeatureWithTies                            // FETCH FIRST ... WITH TIES, or TOP ... WITH TIES
+	FeatureRowLocking                          // SELECT ... FOR UPDATE, or its equivalent
+	FeatureLockShare                           // SELECT ... FOR SHARE
+	FeatureKeyLocks                            // SELECT ... FOR NO KEY UPDATE and FOR KEY SHARE
+	FeatureNoWait                              // NOWAIT locking
+	FeatureSkipLocked                          // SKIP LOCKED locking
+	FeatureLockOf                              // locking only the tables given to LockOf
+	FeatureCTE                                 // WITH common table expressions
+	FeatureMerge                               // MERGE statements
+)
+
+var featureNames = map[Feature]string{
+	FeatureReturning:       "RETURNING",
+	FeatureUpsert:          "upsert",
+	FeatureReplace:         "INSERT OR REPLACE",
+	FeatureRightJoin:       "RIGHT JOIN",
+	FeatureFullOuterJoin:   "FULL OUTER JOIN",
+	FeatureNaturalJoin:     "NATURAL JOIN",
+	FeatureJoinUsing:       "JOIN USING",
+	FeatureLateral:         "LATERAL join",
+	FeatureDistinctOn:      "DISTINCT ON",
+	FeatureWindowFunctions: "window functions",
+	FeatureFilter:          "FILTER",
+	FeatureNullsOrdering:   "NULLS FIRST/LAST",
+	FeatureRowValues:       "row values",
+	FeatureRollup:          "ROLLUP",
+	FeatureCube:            "CUBE",
+	FeatureGroupingSets:    "GROUPING SETS",
+	FeatureWithTies:        "WITH TIES",
+	FeatureRowLocking:      "row locking",
+	FeatureLockShare:       "FOR SHARE",
+	FeatureKeyLocks:        "FOR NO KEY UPDATE/FOR KEY SHARE",
+	FeatureNoWait:          "NOWAIT",
+	FeatureSkipLocked:      "SKIP LOCKED",
+	FeatureLockOf:          "FOR ... OF",
+	FeatureCTE:             "common table expressions",
+	FeatureMerge:           "MERGE",
+}
+
+func (f Feature) String() string {
+	if s, ok := featureNames[f]; ok {
+		return s
+	}
+	return fmt.Sprintf("Feature(%d)", uint64(f))
+}
+
+// Features is a set of features.
+type Features Feature
+
+// NewFeatures returns a set holding the features 'f'.
+func NewFeatures(f ...Feature) Features {
+	var fs Features
+	for _, x := range f {
+		fs |= Features(x)
+	}
+	return fs
+}
+
+// Has is true if the set holds feature 'f'.
+func (fs Features) Has(f Feature) bool {
+	return fs&Features(f) == Features(f)
+}
+
+// With returns the set with the features 'f' added.
+func (fs Features) With(f ...Feature) Features {
+	return fs | NewFeatures(f...)
+}
+
+// Without returns the set with the features 'f' removed.
+func (fs Features) Without(f ...Feature) Features {
+	return fs &^ NewFeatures(f...)
+}
+
+// UnsupportedError is the error reported when a statement uses features that its
+// dialect does not support.
+type UnsupportedError struct {
+	Dialect  Dialect
+	Features []Feature
+}
+
+func (e *UnsupportedError) Error() string {
+	names := make([]string, len(e.Features))
+	for i, f := range e.Features {
+		names[i] = f.String()
+	}
+	return fmt.Sprintf("sqlbuilder: %T does not support %s", e.Dialect, strings.Join(names, ", "))
+}
+
+// checkFeatures returns an UnsupportedError for any features in 'used' that the dialect
+// does not support, or nil if there are none.
+func checkFeatures(dialect Dialect, used []Feature) error {
+	supported := dialect.Features()
+	var missing []Feature
+	seen := make(map[Feature]bool)
+	for _, f := range used {
+		if !supported.Has(f) && !seen[f] {
+			missing = append(missing, f)
+			seen[f] = true
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return &UnsupportedError{dialect, missing}
+}
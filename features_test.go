@@ -0,0 +1,138 @@
+package sqlbuilder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFeatures(t *testing.T) {
+	fs := NewFeatures(FeatureReturning, FeatureCTE)
+
+	if !fs.Has(FeatureReturning) || !fs.Has(FeatureCTE) || fs.Has(FeatureMerge) {
+		t.Errorf("bad features: %b", fs)
+	}
+
+	fs = fs.With(FeatureMerge).Without(FeatureCTE)
+	if !fs.Has(FeatureMerge) || fs.Has(FeatureCTE) {
+		t.Errorf("bad features: %b", fs)
+	}
+}
+
+func TestSelectValidate(t *testing.T) {
+	s := Select().
+		Dialect(MySQL).
+		From("customers").As("c").
+		FullOuter().Join("orders").As("o").On("o.customer_id", "c.id").
+		DistinctOn("c.id").
+		LockKeyShare()
+
+	err := s.Validate()
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	expected := []Feature{FeatureDistinctOn, FeatureFullOuterJoin, FeatureKeyLocks}
+	if !reflect.DeepEqual(err.(*UnsupportedError).Features, expected) {
+		t.Errorf("bad features: %v", err.(*UnsupportedError).Features)
+	}
+
+	expectedMessage := "sqlbuilder: sqlbuilder.MySQLDialect does not support DISTINCT ON, FULL OUTER JOIN, FOR NO KEY UPDATE/FOR KEY SHARE"
+	if err.Error() != expectedMessage {
+		t.Errorf("bad message: %q", err.Error())
+	}
+
+	if err := s.Dialect(Postgres).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	err = Select().Dialect(SQLServer).From("a").Natural().Join("b").Using("id").Validate()
+	expected = []Feature{FeatureNaturalJoin, FeatureJoinUsing}
+	if err == nil || !reflect.DeepEqual(err.(*UnsupportedError).Features, expected) {
+		t.Errorf("bad error: %v", err)
+	}
+}
+
+func TestInsertValidate(t *testing.T) {
+	var id int
+	s := Insert().Into("customers").Set("name", "John").Return("id", &id)
+
+	err := s.Dialect(MySQL).Validate()
+	if err == nil || !reflect.DeepEqual(err.(*UnsupportedError).Features, []Feature{FeatureReturning}) {
+		t.Errorf("bad error: %v", err)
+	}
+
+	for _, d := range []Dialect{Postgres, SQLite, SQLServer, Oracle} {
+		if err := s.Dialect(d).Validate(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestVersionedFeatures(t *testing.T) {
+	cases := []struct {
+		dialect   Dialect
+		feature   Feature
+		supported bool
+	}{
+		{MySQL, FeatureWindowFunctions, true},
+		{MySQL.Version(8), FeatureWindowFunctions, true},
+		{MySQL.Version(5, 7), FeatureWindowFunctions, false},
+		{MySQL.Version(5, 7), FeatureLockOf, false},
+		{MySQL.Version(8), FeatureLockOf, true},
+		{MySQL.Version(8, 0, 13), FeatureLateral, false},
+		{MySQL.Version(8, 0, 14), FeatureLateral, true},
+		{Postgres.Version(12), FeatureWithTies, false},
+		{Postgres.Version(13), FeatureWithTies, true},
+		{Postgres.Version(14), FeatureMerge, false},
+		{Postgres.Version(15), FeatureMerge, true},
+		{SQLite.Version(3, 24), FeatureUpsert, true},
+		{SQLite.Version(3, 23, 1), FeatureUpsert, false},
+		{SQLite.Version(3, 34), FeatureReturning, false},
+		{SQLite.Version(3, 35), FeatureReturning, true},
+		{SQLite.Version(3, 38), FeatureRightJoin, false},
+		{Oracle.Version(11, 2), FeatureWithTies, false},
+		{Oracle.Version(12, 1), FeatureWithTies, true},
+	}
+
+	for i, c := range cases {
+		if c.dialect.Features().Has(c.feature) != c.supported {
+			t.Errorf("%d: %T %v: expected %v", i, c.dialect, c.feature, c.supported)
+		}
+	}
+}
+
+func TestVersionedRendering(t *testing.T) {
+	query, _, _ := Select().
+		Dialect(MySQL.Version(5, 7)).
+		From("customers").
+		Map("id", nil).
+		LockShare().
+		Build()
+	expected := "SELECT id\n FROM customers\n LOCK IN SHARE MODE"
+	if query != expected {
+		t.Errorf("bad query: %q", query)
+	}
+
+	for i, s := range []SelectStatement{Select().Lock().LockOf("c"), Select().LockShare().LockOf("c")} {
+		err := s.Dialect(MySQL.Version(5, 7)).From("customers").As("c").Validate()
+		if err == nil || err.Error() != "sqlbuilder: sqlbuilder.MySQLDialect does not support FOR ... OF" {
+			t.Errorf("%d: bad error: %v", i, err)
+		}
+	}
+
+	query, _, _ = Select().
+		Dialect(SQLite.Version(3, 28)).
+		From("customers").
+		Map("id", nil).
+		OrderBy("phone").NullsLast().
+		Build()
+	expected = "SELECT id\n FROM customers\n ORDER BY phone IS NULL, phone"
+	if query != expected {
+		t.Errorf("bad query: %q", query)
+	}
+
+	err := Select().Dialect(SQLite.Version(3, 30)).From("customers").Map("id", nil).Validate()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package sqlbuilder
+
+import (
+	"errors"
+	"strings"
+)
+
+type grouping struct {
+	kind string
+	cols []string
+	sets [][]string
+}
+
+// GroupBy returns a new statement with grouping by 'group', which may be a list of
+// column names or expressions. Multiple GroupBy() calls can be used.
+func (s SelectStatement) GroupBy(group ...string) SelectStatement {
+	return s.addGrouping(grouping{cols: group})
+}
+
+// GroupByRollup returns a new statement with ROLLUP grouping by 'col', which gives
+// subtotals for each prefix of the columns and a grand total.
+// MySQL uses WITH ROLLUP instead, so it must then be the only grouping.
+// This needs FeatureRollup.
+// Multiple GroupBy() calls can be used.
+func (s SelectStatement) GroupByRollup(col ...string) SelectStatement {
+	return s.addGrouping(grouping{kind: "ROLLUP", cols: col})
+}
+
+// GroupByCube returns a new statement with CUBE grouping by 'col', which gives
+// subtotals for every combination of the columns. This needs FeatureCube.
+// Multiple GroupBy() calls can be used.
+func (s SelectStatement) GroupByCube(col ...string) SelectStatement {
+	return s.addGrouping(grouping{kind: "CUBE", cols: col})
+}
+
+// GroupByGroupingSets returns a new statement with GROUPING SETS grouping, each set being
+// a list of columns; an empty set gives the grand total. This needs FeatureGroupingSets.
+// Multiple GroupBy() calls can be used.
+func (s SelectStatement) GroupByGroupingSets(sets ...[]string) SelectStatement {
+	return s.addGrouping(grouping{kind: "GROUPING SETS", sets: sets})
+}
+
+// addGrouping adds the grouping 'g', unless it has no columns.
+func (s SelectStatement) addGrouping(g grouping) SelectStatement {
+	if len(g.cols) == 0 && len(g.sets) == 0 {
+		return s
+	}
+	s.group = append(s.group, g)
+	return s
+}
+
+func (g grouping) build() string {
+	switch g.kind {
+	case "":
+		return strings.Join(g.cols, ", ")
+	case "GROUPING SETS":
+		sets := make([]string, len(g.sets))
+		for i, set := range g.sets {
+			sets[i] = "(" + strings.Join(set, ", ") + ")"
+		}
+		return g.kind + " (" + strings.Join(sets, ", ") + ")"
+	}
+	return g.kind + " (" + strings.Join(g.cols, ", ") + ")"
+}
+
+// checkGroupBy checks that the groupings can be rendered for the dialect.
+func checkGroupBy(groups []grouping, dialect Dialect) error {
+	if isMySQL(dialect) && len(groups) > 1 {
+		for _, g := range groups {
+			if g.kind == "ROLLUP" {
+				return errors.New("sqlbuilder: MySQL WITH ROLLUP must be the only grouping")
+			}
+		}
+	}
+	return nil
+}
+
+// buildGroupBy renders the GROUP BY clause for the dialect.
+func buildGroupBy(groups []grouping, dialect Dialect) string {
+	if isMySQL(dialect) && len(groups) == 1 && groups[0].kind == "ROLLUP" {
+		return "\n GROUP BY " + strings.Join(groups[0].cols, ", ") + " WITH ROLLUP"
+	}
+
+	parts := make([]string, len(groups))
+	for i, g := range groups {
+		parts[i] = g.build()
+	}
+	return "\n GROUP BY " + strings.Join(parts, ", ")
+}
+
+// features lists the optional features that the grouping uses.
+func (g grouping) features() []Feature {
+	switch g.kind {
+	case "ROLLUP":
+		return []Feature{FeatureRollup}
+	case "CUBE":
+		return []Feature{FeatureCube}
+	case "GROUPING SETS":
+		return []Feature{FeatureGroupingSets}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package sqlbuilder
 
 import (
+	"database/sql"
 	"fmt"
 	"strings"
 )
@@ -23,11 +24,20 @@ type insertRet struct {
 
 // InsertStatement represents an INSERT statement.
 type InsertStatement struct {
-	dialect Dialect
-	last    lastWas
-	table   name
-	sets    []insertSet
-	rets    []insertRet
+	dialect  Dialect
+	schema   string
+	resolver TableResolver
+	last     lastWas
+	table    name
+	sets     []insertSet
+	rets     []insertRet
+	replace  bool
+	upsert   *upsert
+}
+
+type upsert struct {
+	key    []string
+	update []string
 }
 
 // Dialect returns a new statement with dialect set to 'dialect'.
@@ -36,9 +46,16 @@ func (s InsertStatement) Dialect(dialect Dialect) InsertStatement {
 	return s
 }
 
+// Schema returns a new statement in which table names that have no schema are qualified
+// by 'schema'. This overrides any default schema of the dialect.
+func (s InsertStatement) Schema(schema string) InsertStatement {
+	s.schema = schema
+	return s
+}
+
 // Into returns a new statement with the table to insert into set to 'table'.
 func (s InsertStatement) Into(table string) InsertStatement {
-	s.table = name{table, ""}
+	s.table = tableName(table)
 	s.last = lastWasTableName
 	return s
 }
@@ -47,7 +64,7 @@ func (s InsertStatement) Into(table string) InsertStatement {
 func (s InsertStatement) As(alias string) InsertStatement {
 	switch s.last {
 	case lastWasTableName:
-		s.table = name{s.table.name, alias}
+		s.table.alias = alias
 	}
 	s.last = lastWasUnknown
 	return s
@@ -65,14 +82,43 @@ func (s InsertStatement) SetSQL(col, sql string) InsertStatement {
 	return s
 }
 
-// Return returns a new statement with a RETURNING clause.
+// OrReplace returns a new statement that replaces any existing row that has the same key.
+// SQLite renders this as INSERT OR REPLACE and MySQL as REPLACE; other dialects do not
+// support this.
+func (s InsertStatement) OrReplace() InsertStatement {
+	s.replace = true
+	return s
+}
+
+// OnConflictUpdate returns a new statement that, if the new row conflicts with an existing
+// row on the unique columns 'key', updates the columns 'col' of the existing row instead.
+// Postgres and SQLite render this as ON CONFLICT (key) DO UPDATE; MySQL uses
+// ON DUPLICATE KEY UPDATE, which applies to any unique key so 'key' is not used.
+// From MySQL 8.0.20, the new row is referred to by a row alias rather than VALUES(col).
+func (s InsertStatement) OnConflictUpdate(key []string, col ...string) InsertStatement {
+	s.upsert = &upsert{key, col}
+	return s
+}
+
+// Return returns a new statement with a RETURNING clause. SQL Server uses an OUTPUT clause
+// instead, in which plain column names refer to the INSERTED row.
+//
+// Oracle uses RETURNING ... INTO, so 'dest' is passed as an sql.Out argument rather than
+// in the destination slice; the query must then be run with Exec.
 func (s InsertStatement) Return(col string, dest interface{}) InsertStatement {
 	s.rets = append(s.rets, insertRet{sql: col, dest: dest})
 	return s
 }
 
 // Build builds the SQL query. It returns the SQL query and the argument slice.
+//
+// Build panics if the statement uses features that its dialect does not support;
+// use Validate to check this first.
 func (s InsertStatement) Build() (query string, args []interface{}, dest []interface{}) {
+	if err := s.Validate(); err != nil {
+		panic(err)
+	}
+
 	var cols, vals []string
 	idx := 0
 
@@ -82,27 +128,116 @@ func (s InsertStatement) Build() (query string, args []interface{}, dest []inter
 		if set.raw {
 			vals = append(vals, set.arg.(string))
 		} else {
-			args = append(args, set.arg)
-			vals = append(vals, s.dialect.Placeholder(idx))
-			idx++
+			var p string
+			p, args, idx = bind(s.dialect, args, idx, set.col, set.arg)
+			vals = append(vals, p)
 		}
 	}
 
-	returning := ""
+	output, returning := "", ""
 	if len(s.rets) > 0 {
-		var args []string
+		var rets []string
 		for _, ret := range s.rets {
-			args = append(args, ret.sql)
-			dest = append(dest, ret.dest)
+			rets = append(rets, ret.sql)
+		}
+
+		switch s.dialect.(type) {
+		case SQLServerDialect:
+			for i, r := range rets {
+				if isPlainIdentifier(r) {
+					rets[i] = "INSERTED." + r
+				}
+			}
+			output = " OUTPUT " + strings.Join(rets, ", ")
+
+		case OracleDialect:
+			var into []string
+			for _, ret := range s.rets {
+				var p string
+				p, args, idx = bind(s.dialect, args, idx, ret.sql, sql.Out{Dest: ret.dest})
+				into = append(into, p)
+			}
+			returning = " RETURNING " + strings.Join(rets, ", ") + " INTO " + strings.Join(into, ", ")
+
+		default:
+			returning = " RETURNING " + strings.Join(rets, ", ")
+		}
+
+		if !isOracle(s.dialect) {
+			for _, ret := range s.rets {
+				dest = append(dest, ret.dest)
+			}
 		}
-		returning = " RETURNING " + strings.Join(args, ", ")
 	}
 
-	query = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)%s",
-		s.table,
+	table := make(tableRefs).resolve(s.table.qualify(s.schema, s.dialect), resolverOrDefault(s.resolver))
+	query = fmt.Sprintf("%s %s (%s)%s VALUES (%s)%s%s",
+		s.buildVerb(),
+		table.QuotedAs(s.dialect),
 		strings.Join(cols, ", "),
+		output,
 		strings.Join(vals, ", "),
+		s.buildUpsert(),
 		returning)
 
 	return
 }
+
+// Validate checks that the dialect supports all the features used by the statement.
+// If not, it returns an *UnsupportedError.
+func (s InsertStatement) Validate() error {
+	var used []Feature
+	if len(s.rets) > 0 {
+		used = append(used, FeatureReturning)
+	}
+	if s.replace {
+		used = append(used, FeatureReplace)
+	}
+	if s.upsert != nil {
+		used = append(used, FeatureUpsert)
+	}
+	return checkFeatures(s.dialect, used)
+}
+
+func (s InsertStatement) buildVerb() string {
+	if !s.replace {
+		return "INSERT INTO"
+	}
+
+	switch s.dialect.(type) {
+	case SQLiteDialect:
+		return "INSERT OR REPLACE INTO"
+	case MySQLDialect:
+		return "REPLACE INTO"
+	}
+	panic(fmt.Sprintf("sqlbuilder: INSERT OR REPLACE is not supported by %T", s.dialect))
+}
+
+func (s InsertStatement) buildUpsert() string {
+	if s.upsert == nil {
+		return ""
+	}
+
+	sets := make([]string, len(s.upsert.update))
+
+	switch s.dialect.(type) {
+	case PostgresDialect, SQLiteDialect:
+		for i, c := range s.upsert.update {
+			sets[i] = c + " = excluded." + c
+		}
+		return " ON CONFLICT (" + strings.Join(s.upsert.key, ", ") + ") DO UPDATE SET " + strings.Join(sets, ", ")
+
+	case MySQLDialect:
+		if d := s.dialect.(MySQLDialect); d.version.atLeast(8, 0, 20) {
+			for i, c := range s.upsert.update {
+				sets[i] = c + " = new." + c
+			}
+			return " AS new ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+		}
+		for i, c := range s.upsert.update {
+			sets[i] = c + " = VALUES(" + c + ")"
+		}
+		return " ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+	}
+	panic(fmt.Sprintf("sqlbuilder: upsert is not supported by %T", s.dialect))
+}
@@ -1,6 +1,7 @@
 package sqlbuilder
 
 import (
+	"database/sql"
 	"reflect"
 	"testing"
 )
@@ -73,3 +74,170 @@ func TestInsertReturningPostgres(t *testing.T) {
 		t.Errorf("bad dest: %v", dest)
 	}
 }
+
+func TestInsertSQLServer(t *testing.T) {
+	query, args, _ := Insert().
+		Dialect(SQLServer).
+		Into("customers").
+		Set("name", "John").
+		Set("phone", "555").
+		SetSQL("created_at", "GETDATE()").
+		Build()
+
+	expectedQuery := `INSERT INTO customers (name, phone, created_at) VALUES (@p1, @p2, GETDATE())`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{"John", "555"}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestInsertReturningSQLServer(t *testing.T) {
+	var id, one uint
+
+	query, args, dest := Insert().
+		Dialect(SQLServer).
+		Into("customers").
+		Set("name", "John").
+		Set("phone", "555").
+		SetSQL("created_at", "GETDATE()").
+		Return("id", &id).
+		Return("1", &one).
+		Build()
+
+	expectedQuery := `INSERT INTO customers (name, phone, created_at) OUTPUT INSERTED.id, 1 VALUES (@p1, @p2, GETDATE())`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{"John", "555"}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+
+	expectedDest := []interface{}{&id, &one}
+	if !reflect.DeepEqual(dest, expectedDest) {
+		t.Errorf("bad dest: %v", dest)
+	}
+}
+
+func TestInsertReturningOracle(t *testing.T) {
+	var id uint
+
+	query, args, dest := Insert().
+		Dialect(Oracle).
+		Into("customers").
+		Set("name", "John").
+		Set("phone", "555").
+		SetSQL("created_at", "SYSDATE").
+		Return("id", &id).
+		Build()
+
+	expectedQuery := `INSERT INTO customers (name, phone, created_at) VALUES (:1, :2, SYSDATE) RETURNING id INTO :3`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{"John", "555", sql.Out{Dest: &id}}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+
+	if len(dest) != 0 {
+		t.Errorf("bad dest: %v", dest)
+	}
+}
+
+func TestInsertNamedOracle(t *testing.T) {
+	var id uint
+
+	query, args, _ := Insert().
+		Dialect(Oracle.Named()).
+		Into("customers").
+		Set("name", "John").
+		Set("phone", "555").
+		Return("id", &id).
+		Build()
+
+	expectedQuery := `INSERT INTO customers (name, phone) VALUES (:name, :phone) RETURNING id INTO :id`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{sql.Named("name", "John"), sql.Named("phone", "555"), sql.Named("id", sql.Out{Dest: &id})}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestInsertWithSchema(t *testing.T) {
+	query, _, _ := Insert().
+		Dialect(Postgres.Schema("sales")).
+		Into("orders").
+		Set("total", 10).
+		Build()
+
+	expectedQuery := "INSERT INTO sales.orders (total) VALUES ($1)"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+}
+
+func TestInsertUpsert(t *testing.T) {
+	cases := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{Postgres, "INSERT INTO customers (id, name) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET name = excluded.name"},
+		{SQLite, "INSERT INTO customers (id, name) VALUES (?, ?) ON CONFLICT (id) DO UPDATE SET name = excluded.name"},
+		{MySQL, "INSERT INTO customers (id, name) VALUES (?, ?) AS new ON DUPLICATE KEY UPDATE name = new.name"},
+		{MySQL.Version(8, 0, 20), "INSERT INTO customers (id, name) VALUES (?, ?) AS new ON DUPLICATE KEY UPDATE name = new.name"},
+		{MySQL.Version(8, 0, 19), "INSERT INTO customers (id, name) VALUES (?, ?) ON DUPLICATE KEY UPDATE name = VALUES(name)"},
+		{MySQL.Version(5, 7), "INSERT INTO customers (id, name) VALUES (?, ?) ON DUPLICATE KEY UPDATE name = VALUES(name)"},
+	}
+
+	for i, c := range cases {
+		query, _, _ := Insert().
+			Dialect(c.dialect).
+			Into("customers").
+			Set("id", 1).
+			Set("name", "John").
+			OnConflictUpdate([]string{"id"}, "name").
+			Build()
+		if query != c.expected {
+			t.Errorf("%d: bad query: %q", i, query)
+		}
+	}
+}
+
+func TestInsertOrReplaceSQLite(t *testing.T) {
+	var id uint
+
+	query, args, _ := Insert().
+		Dialect(SQLite).
+		Into("customers").
+		Set("id", 1).
+		Set("name", "John").
+		OrReplace().
+		Return("id", &id).
+		Build()
+
+	expectedQuery := `INSERT OR REPLACE INTO customers (id, name) VALUES (?, ?) RETURNING id`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{1, "John"}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+
+	query, _, _ = Insert().Dialect(MySQL).Into("customers").Set("id", 1).OrReplace().Build()
+	expectedQuery = `REPLACE INTO customers (id) VALUES (?)`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+}
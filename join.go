@@ -8,9 +8,10 @@ import (
 type join struct {
 	op       string
 	table    name
-	onL, onR name
+	onL, onR string
+	on       []where
 	using    []string
-	dialect  Dialect
+	lateral  *SelectStatement
 }
 
 // Natural precedes Join when required. Any of the other modifiers Left, LeftOuter,
@@ -33,21 +34,21 @@ func (s SelectStatement) LeftOuter() SelectStatement {
 }
 
 // Right precedes Join when required. Only one join modifier can be used.
-// SQLite does not support right join.
+// This needs FeatureRightJoin.
 func (s SelectStatement) Right() SelectStatement {
 	s.joinOp = "RIGHT "
 	return s
 }
 
 // RightOuter precedes Join when required. Only one join modifier can be used.
-// SQLite does not support right outer join.
+// This needs FeatureRightJoin.
 func (s SelectStatement) RightOuter() SelectStatement {
 	s.joinOp = "RIGHT OUTER "
 	return s
 }
 
 // FullOuter precedes Join when required. Only one join modifier can be used.
-// SQLite does not support full outer join.
+// This needs FeatureFullOuterJoin, which MySQL lacks.
 func (s SelectStatement) FullOuter() SelectStatement {
 	s.joinOp = "FULL OUTER "
 	return s
@@ -69,20 +70,40 @@ func (s SelectStatement) Cross() SelectStatement {
 
 // Join sets the table name for the current join.
 func (s SelectStatement) Join(table string) SelectStatement {
-	s.joinTbl = name{table, ""}
+	s.joinTbl = tableName(table)
 	s.last = lastWasJoinTableName
 	return s
 }
 
-// On completes a JOIN clause with the necessary constraint.
+// On completes a JOIN clause with the necessary constraint, which is that
+// column 'onL' equals column 'onR'.
 // When required, another join can immediately follow this.
 func (s SelectStatement) On(onL, onR string) SelectStatement {
 	op := s.joinNat + s.joinOp + "JOIN"
-	j := join{op, s.joinTbl, splitAsName(onL), splitAsName(onR), nil, s.dialect}
-	s.joins = append(s.joins, j)
-	s.joinNat = ""
-	s.joinOp = ""
-	s.joinTbl = name{}
+	j := join{op: op, table: s.joinTbl, onL: onL, onR: onR}
+	return s.addJoin(j)
+}
+
+// OnSQL completes a JOIN clause with an arbitrary condition and the necessary arguments
+// to that condition. For example OnSQL("o.created > c.since") or OnSQL("o.status = ?", "paid").
+// When required, another join can immediately follow this.
+func (s SelectStatement) OnSQL(cond string, args ...interface{}) SelectStatement {
+	op := s.joinNat + s.joinOp + "JOIN"
+	j := join{op: op, table: s.joinTbl, on: []where{{"", cond, args}}}
+	return s.addJoin(j)
+}
+
+// AndOn adds a further condition to the preceding join, with the necessary arguments
+// to that condition. All the conditions of a join are combined with AND.
+// This panics if there hasn't been a join completed with On or OnSQL yet.
+func (s SelectStatement) AndOn(cond string, args ...interface{}) SelectStatement {
+	i := len(s.joins) - 1
+	if i < 0 || len(s.joins[i].using) > 0 || s.joins[i].lateral != nil {
+		panic("sqlbuilder: AndOn without a preceding On or OnSQL")
+	}
+	j := s.joins[i]
+	j.on = append(j.on[:len(j.on):len(j.on)], where{"", cond, args})
+	s.joins = append(s.joins[:i:i], j)
 	return s
 }
 
@@ -90,7 +111,28 @@ func (s SelectStatement) On(onL, onR string) SelectStatement {
 // When required, another join can immediately follow this.
 func (s SelectStatement) Using(col ...string) SelectStatement {
 	op := s.joinNat + s.joinOp + "JOIN"
-	j := join{op, s.joinTbl, name{}, name{}, col, s.dialect}
+	j := join{op: op, table: s.joinTbl, using: col}
+	return s.addJoin(j)
+}
+
+// JoinLateral adds a LATERAL join to the subquery 'sub', which may refer to columns of
+// the tables that precede it. An alias is needed, so As must follow this. The join is
+// complete, so no On or Using is needed; when required, another join can immediately
+// follow.
+//
+// Postgres and MySQL render this as JOIN LATERAL (...) alias ON true; the Left and
+// Cross modifiers are the only others that are normally useful, and the latter omits
+// ON true. SQL Server and Oracle use CROSS APPLY, or OUTER APPLY with the Left
+// modifier. This needs FeatureLateral, which SQLite lacks.
+func (s SelectStatement) JoinLateral(sub SelectStatement) SelectStatement {
+	op := s.joinNat + s.joinOp + "JOIN"
+	j := join{op: op, lateral: &sub}
+	s = s.addJoin(j)
+	s.last = lastWasLateralJoin
+	return s
+}
+
+func (s SelectStatement) addJoin(j join) SelectStatement {
 	s.joins = append(s.joins, j)
 	s.joinNat = ""
 	s.joinOp = ""
@@ -98,14 +140,76 @@ func (s SelectStatement) Using(col ...string) SelectStatement {
 	return s
 }
 
-func (j join) String() string {
-	tbl := j.table.QuotedAs(j.dialect)
+// build renders the join, with the table hint 'hint' if needed. Any arguments to the
+// join conditions are appended to 'args'.
+func (j join) build(args []interface{}, idx int, dialect Dialect, hint string) (string, []interface{}, int) {
+	if j.lateral != nil {
+		return j.buildLateral(args, idx, dialect)
+	}
+
+	tbl := j.table.QuotedAs(dialect) + hint
 	if len(j.using) > 0 {
 		cols := strings.Join(j.using, ", ")
-		return fmt.Sprintf("\n %s %s USING (%s)", j.op, tbl, cols)
-	} else {
-		onL := j.onL.QuotedDot(j.dialect)
-		onR := j.onR.QuotedDot(j.dialect)
-		return fmt.Sprintf("\n %s %s ON %s = %s", j.op, tbl, onL, onR)
+		return fmt.Sprintf("\n %s %s USING (%s)", j.op, tbl, cols), args, idx
+	}
+
+	var conds []string
+	if j.onL != "" {
+		conds = append(conds, quoteName(dialect, j.onL)+" = "+quoteName(dialect, j.onR))
+	}
+
+	var more []string
+	more, args, idx = buildConditions(args, idx, j.on, dialect)
+	conds = append(conds, more...)
+
+	on := conds[0]
+	if len(conds) > 1 {
+		on = "(" + strings.Join(conds, ") AND (") + ")"
+	}
+	return fmt.Sprintf("\n %s %s ON %s", j.op, tbl, on), args, idx
+}
+
+func (j join) buildLateral(args []interface{}, idx int, dialect Dialect) (string, []interface{}, int) {
+	if j.table.alias == "" {
+		panic("sqlbuilder: LATERAL join without an alias")
+	}
+
+	var sub string
+	sub, args, _, idx = j.lateral.Dialect(dialect).build(args, idx)
+
+	switch dialect.(type) {
+	case SQLServerDialect, OracleDialect:
+		apply := "CROSS APPLY"
+		if strings.HasPrefix(j.op, "LEFT") {
+			apply = "OUTER APPLY"
+		}
+		return fmt.Sprintf("\n %s (%s) %s", apply, sub, j.table.alias), args, idx
+	}
+	if strings.HasPrefix(j.op, "CROSS") {
+		// CROSS JOIN takes no ON clause
+		return fmt.Sprintf("\n %s LATERAL (%s) %s", j.op, sub, j.table.alias), args, idx
+	}
+	return fmt.Sprintf("\n %s LATERAL (%s) %s ON true", j.op, sub, j.table.alias), args, idx
+}
+
+// features lists the optional features that the join uses.
+func (j join) features(dialect Dialect) []Feature {
+	var used []Feature
+	if strings.Contains(j.op, "RIGHT ") {
+		used = append(used, FeatureRightJoin)
+	}
+	if strings.Contains(j.op, "FULL ") {
+		used = append(used, FeatureFullOuterJoin)
+	}
+	if strings.Contains(j.op, "NATURAL ") {
+		used = append(used, FeatureNaturalJoin)
+	}
+	if len(j.using) > 0 {
+		used = append(used, FeatureJoinUsing)
+	}
+	if j.lateral != nil {
+		used = append(used, FeatureLateral)
+		used = append(used, j.lateral.Dialect(dialect).features()...)
 	}
+	return used
 }
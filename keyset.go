@@ -0,0 +1,83 @@
+package sqlbuilder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SeekAfter returns a new statement that fetches the page of up to 'limit' rows following
+// the row whose ORDER BY values were 'last'. This is keyset pagination: unlike Offset, it
+// stays fast on deep pages provided there is an index matching the ordering.
+//
+// The values must correspond one-to-one with the columns given to OrderBy, which should
+// together be unique; each column is compared according to its own Desc flag.
+// A nil or empty 'last' fetches the first page.
+func (s SelectStatement) SeekAfter(limit int, last ...interface{}) SelectStatement {
+	s.seek = last
+	s.limit = &limit
+	return s
+}
+
+// checkSeek checks that the seek values match the ORDER BY columns.
+func (s SelectStatement) checkSeek() error {
+	if len(s.seek) != len(s.order) {
+		return fmt.Errorf("sqlbuilder: SeekAfter has %d values but there are %d ORDER BY columns",
+			len(s.seek), len(s.order))
+	}
+
+	for _, o := range s.order {
+		if len(o.args) > 0 {
+			return errors.New("sqlbuilder: SeekAfter cannot be used with OrderByExpr arguments")
+		}
+	}
+	return nil
+}
+
+// seekCondition builds the where-clause that selects the rows after the seek values.
+// When all the columns are sorted the same way and the dialect supports row values, this
+// is a single comparison such as (a, b) > (?, ?); otherwise it is expanded to
+// (a > ?) OR (a = ? AND b > ?).
+func (s SelectStatement) seekCondition() where {
+	if err := s.checkSeek(); err != nil {
+		panic(err)
+	}
+
+	uniform := true
+	cols := make([]string, len(s.order))
+	for i, o := range s.order {
+		cols[i] = o.col
+		uniform = uniform && o.desc == s.order[0].desc
+	}
+
+	if uniform && len(cols) > 1 && s.dialect.Features().Has(FeatureRowValues) {
+		op := seekOperator(s.order[0])
+		marks := strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", ")
+		return where{"(" + strings.Join(cols, ", ") + ")", op + " (" + marks + ")", s.seek}
+	}
+
+	var terms []string
+	var args []interface{}
+	for i, o := range s.order {
+		var term []string
+		for j := 0; j < i; j++ {
+			term = append(term, cols[j]+" = ?")
+			args = append(args, s.seek[j])
+		}
+		term = append(term, cols[i]+" "+seekOperator(o)+" ?")
+		args = append(args, s.seek[i])
+		terms = append(terms, strings.Join(term, " AND "))
+	}
+
+	if len(terms) == 1 {
+		return where{"", terms[0], args}
+	}
+	return where{"", "(" + strings.Join(terms, ") OR (") + ")", args}
+}
+
+func seekOperator(o order) string {
+	if o.desc {
+		return "<"
+	}
+	return ">"
+}
@@ -0,0 +1,157 @@
+package sqlbuilder
+
+import (
+	"fmt"
+	"strings"
+)
+
+type lock struct {
+	strength string
+	wait     string
+	of       []string
+}
+
+// Lock returns a new statement with FOR UPDATE locking.
+func (s SelectStatement) Lock() SelectStatement {
+	s.lock = &lock{strength: "UPDATE"}
+	return s
+}
+
+// LockShare returns a new statement with FOR SHARE locking.
+func (s SelectStatement) LockShare() SelectStatement {
+	s.lock = &lock{strength: "SHARE"}
+	return s
+}
+
+// LockNoKeyUpdate returns a new statement with FOR NO KEY UPDATE locking.
+// This needs FeatureKeyLocks, which only Postgres has.
+func (s SelectStatement) LockNoKeyUpdate() SelectStatement {
+	s.lock = &lock{strength: "NO KEY UPDATE"}
+	return s
+}
+
+// LockKeyShare returns a new statement with FOR KEY SHARE locking.
+// This needs FeatureKeyLocks, which only Postgres has.
+func (s SelectStatement) LockKeyShare() SelectStatement {
+	s.lock = &lock{strength: "KEY SHARE"}
+	return s
+}
+
+// NoWait modifies the preceding lock so that the query fails rather than waiting
+// for rows locked by other transactions.
+// This panics if there hasn't been a lock yet.
+func (s SelectStatement) NoWait() SelectStatement {
+	return s.modifyLock(func(l *lock) { l.wait = "NOWAIT" })
+}
+
+// SkipLocked modifies the preceding lock so that rows locked by other transactions
+// are skipped. This is useful for job queues.
+// This panics if there hasn't been a lock yet.
+func (s SelectStatement) SkipLocked() SelectStatement {
+	return s.modifyLock(func(l *lock) { l.wait = "SKIP LOCKED" })
+}
+
+// LockOf modifies the preceding lock so that only rows of the tables 'table' are locked.
+// Aliases should be used for tables that have them. Oracle expects columns instead,
+// e.g. "j.id", and locks the tables that they belong to. This needs FeatureLockOf,
+// which MySQL has from 8.0. This panics if there hasn't been a lock yet.
+func (s SelectStatement) LockOf(table ...string) SelectStatement {
+	return s.modifyLock(func(l *lock) { l.of = append(l.of[:len(l.of):len(l.of)], table...) })
+}
+
+func (s SelectStatement) modifyLock(fn func(*lock)) SelectStatement {
+	if s.lock == nil {
+		panic("sqlbuilder: lock modifier without a preceding Lock")
+	}
+	l := *s.lock
+	fn(&l)
+	s.lock = &l
+	return s
+}
+
+// build renders the locking clause for the dialect. SQL Server has no locking clause
+// because it uses table hints instead. SQLite has no row locks at all, so the clause is
+// dropped unless the dialect rejects locks.
+func (l lock) build(dialect Dialect) string {
+	if isSQLServer(dialect) || dropsLocks(dialect) {
+		return ""
+	}
+	if d, ok := dialect.(MySQLDialect); ok && l.strength == "SHARE" && !d.version.atLeast(8) {
+		return "\n LOCK IN SHARE MODE"
+	}
+	return l.buildFor()
+}
+
+// features lists the optional features that the lock uses.
+func (l lock) features(dialect Dialect) []Feature {
+	if dropsLocks(dialect) {
+		return nil
+	}
+
+	used := []Feature{FeatureRowLocking}
+	switch l.strength {
+	case "SHARE":
+		used = append(used, FeatureLockShare)
+	case "NO KEY UPDATE", "KEY SHARE":
+		used = append(used, FeatureKeyLocks)
+	}
+	switch l.wait {
+	case "NOWAIT":
+		used = append(used, FeatureNoWait)
+	case "SKIP LOCKED":
+		used = append(used, FeatureSkipLocked)
+	}
+	if len(l.of) > 0 {
+		used = append(used, FeatureLockOf)
+	}
+	return used
+}
+
+func (l lock) buildFor() string {
+	sql := "\n FOR " + l.strength
+	if len(l.of) > 0 {
+		sql += " OF " + strings.Join(l.of, ", ")
+	}
+	if l.wait != "" {
+		sql += " " + l.wait
+	}
+	return sql
+}
+
+// tableHint renders the SQL Server table hint for table 't', if the lock applies to it.
+// All tables are locked unless LockOf was used.
+func (l lock) tableHint(t name, dialect Dialect) string {
+	if !isSQLServer(dialect) {
+		return ""
+	}
+
+	if len(l.of) > 0 {
+		found := false
+		for _, of := range l.of {
+			found = found || of == t.name || (of == t.alias && t.alias != "") ||
+				(t.schema != "" && of == t.schema+"."+t.name)
+		}
+		if !found {
+			return ""
+		}
+	}
+
+	var hints []string
+	switch l.strength {
+	case "UPDATE":
+		hints = []string{"UPDLOCK", "ROWLOCK"}
+	case "SHARE":
+		hints = []string{"HOLDLOCK", "ROWLOCK"}
+	default:
+		panic(fmt.Sprintf("sqlbuilder: FOR %s is not supported by %T", l.strength, dialect))
+	}
+
+	switch l.wait {
+	case "NOWAIT":
+		hints = append(hints, "NOWAIT")
+	case "SKIP LOCKED":
+		hints = append(hints, "READPAST")
+	}
+
+	return " WITH (" + strings.Join(hints, ", ") + ")"
+}
@@ -8,43 +8,179 @@ const (
 	lastWasUnknown lastWas = iota
 	lastWasTableName
 	lastWasJoinTableName
+	lastWasLateralJoin
 	lastWasColumnName
 )
 
+// name is a table or column name with an optional alias. A table name may be qualified
+// by a schema, which may itself be qualified, e.g. by a database in SQL Server.
 type name struct {
-	name, alias string
+	schema, name, alias string
 }
 
-func splitAsName(s string) name {
-	a := strings.Split(s, ".")
-	switch len(a) {
-	case 1:
-		return name{s, ""}
-	case 2:
-		return name{a[0], a[1]}
-	}
-	panic("No way to split '" + s + "' at the dot")
+// schemaDialect is implemented by dialects that can have a default schema.
+type schemaDialect interface {
+	// defaultSchema returns the schema for unqualified table names, or "" for none.
+	defaultSchema() string
 }
 
-func (n name) QuotedAs(dialect Dialect) string {
-	return n.quotedX(dialect, " AS ")
+// tableName splits a possibly-qualified table name such as "sales.orders" or
+// "db.dbo.orders" into its schema and name.
+func tableName(s string) name {
+	parts := splitName(s)
+	i := len(parts) - 1
+	return name{schema: strings.Join(parts[:i], "."), name: parts[i]}
 }
 
-func (n name) QuotedDot(dialect Dialect) string {
-	return n.quotedX(dialect, ".")
+// qualify returns the name with the schema 'schema' if it has none, or else with the
+// dialect's default schema, if any.
+func (n name) qualify(schema string, dialect Dialect) name {
+	if n.schema != "" || n.name == "" {
+		return n
+	}
+	if schema == "" {
+		if sd, ok := dialect.(schemaDialect); ok {
+			schema = sd.defaultSchema()
+		}
+	}
+	n.schema = schema
+	return n
 }
 
-func (n name) quotedX(dialect Dialect, sep string) string {
+// QuotedAs renders the name with its alias, if any. Oracle does not allow AS before
+// table aliases, so it is omitted for that dialect.
+func (n name) QuotedAs(dialect Dialect) string {
+	qn := n.name
+	if n.schema != "" {
+		qn = n.schema + "." + qn
+	}
+	qn = quoteTableName(dialect, qn)
 	if n.alias == "" {
-		return n.name
+		return qn
 	}
-	return n.name + sep + n.alias
+	return qn + tableAs(dialect) + n.alias
 }
 
 func (n name) String() string {
 	qn := n.name
+	if n.schema != "" {
+		qn = n.schema + "." + qn
+	}
 	if n.alias == "" {
 		return qn
 	}
 	return qn + " AS " + n.alias
 }
+
+// tableAs returns the text that separates a table from its alias.
+func tableAs(dialect Dialect) string {
+	if isOracle(dialect) {
+		return " "
+	}
+	return " AS "
+}
+
+// quoteTableName quotes the parts of a table name, such as "sales.order-items", where the
+// dialect needs it, i.e. parts containing hyphens or other unusual characters, and reserved
+// words. Anything else, such as a table function, is returned unchanged.
+func quoteTableName(dialect Dialect, s string) string {
+	return quoteParts(dialect, s, isIdentifierLike)
+}
+
+// quoteName quotes the reserved words in a dotted column name, such as "o.order". Column
+// names may also be expressions, so anything that is not a list of plain identifiers, such
+// as "o.price * o.qty", "total-discount" or "id::int", is returned unchanged.
+func quoteName(dialect Dialect, s string) string {
+	return quoteParts(dialect, s, isPlainIdentifier)
+}
+
+// quoteParts quotes the parts of a dotted name that need it, provided that every part is
+// either quoted already or accepted by 'isName'. Otherwise, 's' is returned unchanged.
+func quoteParts(dialect Dialect, s string, isName func(string) bool) string {
+	parts := splitName(s)
+	for _, p := range parts {
+		if !isQuotedIdentifier(p) && !isName(p) {
+			return s
+		}
+	}
+
+	for i, p := range parts {
+		if !isQuotedIdentifier(p) && (!isPlainIdentifier(p) || reservedWords[strings.ToUpper(p)]) {
+			parts[i] = dialect.Quote(p)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// isIdentifierLike is true for non-empty strings of letters, digits, underscores, hyphens
+// and dollar signs, i.e. names that are not expressions but may need quoting.
+func isIdentifierLike(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isIdentifierByte(s[i]) && s[i] != '-' && s[i] != '$' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// reservedWords are the common reserved words that need quoting when used as names.
+var reservedWords = map[string]bool{
+	"ALL": true, "AND": true, "AS": true, "ASC": true, "BETWEEN": true, "BY": true, "CASE": true,
+	"CHECK": true, "COLUMN": true, "CREATE": true, "DEFAULT": true, "DELETE": true, "DESC": true,
+	"DISTINCT": true, "ELSE": true, "END": true, "FROM": true, "GRANT": true, "GROUP": true,
+	"HAVING": true, "IN": true, "INDEX": true, "INSERT": true, "IS": true, "JOIN": true, "KEY": true,
+	"LIKE": true, "LIMIT": true, "NOT": true, "NULL": true, "OFFSET": true, "ON": true, "OR": true,
+	"ORDER": true, "PRIMARY": true, "REFERENCES": true, "SELECT": true, "SET": true, "TABLE": true,
+	"THEN": true, "TO": true, "UNION": true, "UPDATE": true, "USER": true, "USING": true,
+	"VALUES": true, "WHEN": true, "WHERE": true, "WITH": true,
+}
+
+// splitName splits a dotted name into its parts. Dots within quoted parts, such as
+// "a.b", [a.b] or `a.b`, do not split the name.
+func splitName(s string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"', '`':
+			i = skipQuoted(s, i, s[i]) - 1
+		case '[':
+			if j := strings.IndexByte(s[i:], ']'); j > 0 {
+				i += j
+			}
+		case '.':
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// isQuotedIdentifier is true for identifiers enclosed in double quotes, backticks or
+// square brackets.
+func isQuotedIdentifier(s string) bool {
+	if len(s) < 2 {
+		return false
+	}
+	switch s[0] {
+	case '"', '`':
+		return s[len(s)-1] == s[0]
+	case '[':
+		return s[len(s)-1] == ']'
+	}
+	return false
+}
+
+// isPlainIdentifier is true for non-empty strings containing only letters, digits and
+// underscores, and not starting with a digit.
+func isPlainIdentifier(s string) bool {
+	for i, r := range s {
+		switch {
+		case r == '_', 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z':
+		case '0' <= r && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,104 @@
+package sqlbuilder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// OracleDialect is the dialect for Oracle. By default, it targets Oracle 12c or later;
+// use Version for earlier releases.
+type OracleDialect struct {
+	version version
+	named   bool
+	schema  string
+}
+
+// Oracle is the Oracle dialect.
+var Oracle OracleDialect
+
+// Version returns the dialect for a particular release of Oracle, e.g. Oracle.Version(11, 2).
+func (dialect OracleDialect) Version(v ...int) OracleDialect {
+	dialect.version = newVersion(v)
+	return dialect
+}
+
+// Named returns the dialect with parameters bound by name: placeholders are :name and
+// arguments are sql.NamedArg values. Names are taken from the columns, or from any
+// sql.NamedArg arguments supplied.
+func (dialect OracleDialect) Named() OracleDialect {
+	dialect.named = true
+	return dialect
+}
+
+func (dialect OracleDialect) namePrefix() string {
+	if dialect.named {
+		return ":"
+	}
+	return ""
+}
+
+// Schema returns the dialect with a default schema for table names that have none,
+// unless the statement sets its own with Schema.
+func (dialect OracleDialect) Schema(schema string) OracleDialect {
+	dialect.schema = schema
+	return dialect
+}
+
+func (dialect OracleDialect) defaultSchema() string {
+	return dialect.schema
+}
+
+var oracleFeatures = NewFeatures(FeatureReturning, FeatureRightJoin, FeatureFullOuterJoin, FeatureNaturalJoin,
+	FeatureJoinUsing, FeatureLateral, FeatureWindowFunctions, FeatureNullsOrdering, FeatureRollup,
+	FeatureCube, FeatureGroupingSets, FeatureWithTies, FeatureRowLocking, FeatureNoWait, FeatureSkipLocked,
+	FeatureLockOf, FeatureCTE, FeatureMerge)
+
+func (dialect OracleDialect) Placeholder(idx int) string {
+	return ":" + strconv.Itoa(idx+1)
+}
+
+func (dialect OracleDialect) Quote(identifier string) string {
+	return `"` + strings.Replace(identifier, `"`, `""`, -1) + `"`
+}
+
+// Features returns the features of the dialect; LATERAL and WITH TIES need 12c or later.
+func (dialect OracleDialect) Features() Features {
+	if dialect.usesRownum() {
+		return oracleFeatures.Without(FeatureLateral, FeatureWithTies)
+	}
+	return oracleFeatures
+}
+
+// Paginate uses OFFSET and FETCH FIRST. Before 12c, these did not exist so the query is
+// wrapped in a subquery filtered on ROWNUM instead; with an offset, this adds an extra
+// column to the result.
+func (dialect OracleDialect) Paginate(query string, p Pagination) string {
+	if !dialect.usesRownum() {
+		return query + fetchFirst(p, "NEXT")
+	}
+
+	if p.WithTies {
+		panic(fmt.Sprintf("sqlbuilder: WITH TIES is not supported by Oracle %s", dialect.version))
+	}
+
+	if p.Offset == nil {
+		return "SELECT *\n FROM (" + query + ")\n WHERE ROWNUM <= " + strconv.Itoa(*p.Limit)
+	}
+
+	inner := "SELECT q.*, ROWNUM rnum_\n FROM (" + query + ") q"
+	if p.Limit != nil {
+		inner += "\n WHERE ROWNUM <= " + strconv.Itoa(*p.Offset+*p.Limit)
+	}
+	return "SELECT *\n FROM (" + inner + ")\n WHERE rnum_ > " + strconv.Itoa(*p.Offset)
+}
+
+// usesRownum is true for releases before 12c, which have no OFFSET or FETCH FIRST.
+func (dialect OracleDialect) usesRownum() bool {
+	return !dialect.version.atLeast(12)
+}
+
+func isOracle(dialect Dialect) bool {
+	_, ok := dialect.(OracleDialect)
+	return ok
+}
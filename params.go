@@ -0,0 +1,213 @@
+package sqlbuilder
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// params holds the values of named markers such as ':id' in conditions.
+type params map[string]interface{}
+
+// boundParam is the argument for a named marker while a statement is being built. For
+// dialects that bind parameters by name, 'bound' is the name used, which differs from the
+// marker's name if that was taken already.
+type boundParam struct {
+	name  string
+	value interface{}
+	bound string
+}
+
+// with returns a copy of the params with 'values' added.
+func (p params) with(values map[string]interface{}) params {
+	merged := make(params, len(p)+len(values))
+	for k, v := range p {
+		merged[k] = v
+	}
+	for k, v := range values {
+		merged[k] = v
+	}
+	return merged
+}
+
+// structParams gets the values of the fields of struct 'v', named by their 'db' tags or
+// else by the field names. Fields tagged "-" are omitted; embedded structs are flattened.
+func structParams(v interface{}) map[string]interface{} {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("sqlbuilder: BindStruct needs a struct, not %T", v))
+	}
+
+	values := make(map[string]interface{})
+	addStructParams(values, value)
+	return values
+}
+
+func addStructParams(values map[string]interface{}, value reflect.Value) {
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := strings.Split(f.Tag.Get("db"), ",")[0]
+
+		switch {
+		case tag == "-":
+		case f.Anonymous && tag == "" && reflect.Indirect(value.Field(i)).Kind() == reflect.Struct:
+			addStructParams(values, reflect.Indirect(value.Field(i)))
+		case f.PkgPath != "":
+			// unexported
+		case tag != "":
+			values[tag] = value.Field(i).Interface()
+		default:
+			values[f.Name] = value.Field(i).Interface()
+		}
+	}
+}
+
+// bindParams replaces the named markers in each condition with '?', adding the bound
+// values to the condition's arguments in the right order. A slice argument fills as many
+// '?' markers as it has elements, as in where.build.
+// This panics if a marker has no value.
+func bindParams(conds []where, values params) []where {
+	if values == nil {
+		return conds
+	}
+
+	bound := make([]where, len(conds))
+	for i, w := range conds {
+		var args []interface{}
+		next, filled := 0, 0
+		w.sql = replaceMarkers(w.sql, func(marker string) string {
+			if marker == "?" {
+				for next < len(w.args) && markerCount(w.args[next]) == 0 {
+					args = append(args, w.args[next])
+					next++
+				}
+				if next < len(w.args) {
+					if filled == 0 {
+						args = append(args, w.args[next])
+					}
+					filled++
+					if filled >= markerCount(w.args[next]) {
+						next++
+						filled = 0
+					}
+				}
+				return marker
+			}
+
+			name := marker[1:]
+			value, ok := values[name]
+			if !ok {
+				panic(fmt.Sprintf("sqlbuilder: no value bound for %s", marker))
+			}
+			args = append(args, boundParam{name: name, value: value})
+			return "?"
+		})
+		w.args = append(args, w.args[next:]...)
+		bound[i] = w
+	}
+	return bound
+}
+
+// bindParam returns the placeholder for the named marker 'p', which is appended to 'args'
+// unless the same name and value were bound already and the placeholder can be used again.
+// This is not possible for dialects whose placeholders are all '?', nor for Oracle, which
+// binds by position even though its placeholders are numbered, so the value is repeated.
+func bindParam(dialect Dialect, args []interface{}, idx int, p boundParam) (string, []interface{}, int) {
+	if nd, ok := dialect.(namedDialect); ok && nd.namePrefix() != "" {
+		for _, arg := range args {
+			if prev, ok := arg.(boundParam); ok && prev.name == p.name && reflect.DeepEqual(prev.value, p.value) {
+				return nd.namePrefix() + prev.bound, args, idx
+			}
+		}
+		p.bound = uniqueParamName(args, p.name)
+		return nd.namePrefix() + p.bound, append(args, p), idx + 1
+	}
+
+	if dialect.Placeholder(0) != dialect.Placeholder(1) && !isOracle(dialect) {
+		for i, arg := range args {
+			if prev, ok := arg.(boundParam); ok && prev.name == p.name && reflect.DeepEqual(prev.value, p.value) {
+				return dialect.Placeholder(i), args, idx
+			}
+		}
+	}
+	return dialect.Placeholder(idx), append(args, p), idx + 1
+}
+
+// unwrapParams replaces any named markers' arguments with their values, as sql.NamedArg
+// values if they are bound by name.
+func unwrapParams(args []interface{}) []interface{} {
+	for i, arg := range args {
+		if p, ok := arg.(boundParam); ok {
+			args[i] = p.value
+			if p.bound != "" {
+				args[i] = sql.Named(p.bound, p.value)
+			}
+		}
+	}
+	return args
+}
+
+// Bind returns a new statement with values for named markers such as ':id', which may
+// be used in conditions instead of '?'. For example
+//
+//	Where("id", "= :id").Bind(map[string]interface{}{"id": 9})
+//
+// Each marker is rendered as the dialect's placeholder. A name used more than once binds
+// the same value, using the same placeholder where the dialect allows it.
+// Multiple Bind and BindStruct calls can be used.
+func (s SelectStatement) Bind(values map[string]interface{}) SelectStatement {
+	s.params = s.params.with(values)
+	return s
+}
+
+// BindStruct returns a new statement with values for named markers taken from the fields
+// of struct 'v'. Fields are named by their 'db' tags, or else by the field names.
+func (s SelectStatement) BindStruct(v interface{}) SelectStatement {
+	return s.Bind(structParams(v))
+}
+
+// bindParams resolves the named markers in the conditions of the statement.
+func (s SelectStatement) bindParams() SelectStatement {
+	if s.params == nil {
+		return s
+	}
+
+	s.wheres = bindParams(s.wheres, s.params)
+	s.having = bindParams(s.having, s.params)
+
+	joins := make([]join, len(s.joins))
+	for i, j := range s.joins {
+		j.on = bindParams(j.on, s.params)
+		joins[i] = j
+	}
+	s.joins = joins
+	return s
+}
+
+// Bind returns a new statement with values for named markers such as ':id', which may
+// be used in conditions instead of '?'. See SelectStatement.Bind.
+func (s UpdateStatement) Bind(values map[string]interface{}) UpdateStatement {
+	s.params = s.params.with(values)
+	return s
+}
+
+// BindStruct returns a new statement with values for named markers taken from the fields
+// of struct 'v'. See SelectStatement.BindStruct.
+func (s UpdateStatement) BindStruct(v interface{}) UpdateStatement {
+	return s.Bind(structParams(v))
+}
+
+// Bind returns a new statement with values for named markers such as ':id', which may
+// be used in conditions instead of '?'. See SelectStatement.Bind.
+func (s DeleteStatement) Bind(values map[string]interface{}) DeleteStatement {
+	s.params = s.params.with(values)
+	return s
+}
+
+// BindStruct returns a new statement with values for named markers taken from the fields
+// of struct 'v'. See SelectStatement.BindStruct.
+func (s DeleteStatement) BindStruct(v interface{}) DeleteStatement {
+	return s.Bind(structParams(v))
+}
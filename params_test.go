@@ -0,0 +1,164 @@
+package sqlbuilder
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestSelectBind(t *testing.T) {
+	cases := []struct {
+		dialect  Dialect
+		expected string
+		args     []interface{}
+	}{
+		{MySQL, "SELECT id\n FROM customers\n WHERE (name = ? OR nickname = ?) AND (age > ?) AND (id <> ?)",
+			[]interface{}{"John", "John", 18, 9}},
+		{Postgres, "SELECT id\n FROM customers\n WHERE (name = $1 OR nickname = $1) AND (age > $2) AND (id <> $3)",
+			[]interface{}{"John", 18, 9}},
+		{SQLServer.Named(), "SELECT id\n FROM customers\n WHERE (name = @name OR nickname = @name) AND (age > @age) AND (id <> @id)",
+			[]interface{}{sql.Named("name", "John"), sql.Named("age", 18), sql.Named("id", 9)}},
+	}
+
+	for i, c := range cases {
+		query, args, _ := Select().
+			Dialect(c.dialect).
+			From("customers").
+			Map("id", nil).
+			Where("", "name = :name OR nickname = :name").
+			Where("age", "> ?", 18).
+			Where("id", "<> :id").
+			Bind(map[string]interface{}{"name": "John"}).
+			Bind(map[string]interface{}{"id": 9}).
+			Build()
+
+		if query != c.expected {
+			t.Errorf("%d: bad query: %q", i, query)
+		}
+		if !reflect.DeepEqual(args, c.args) {
+			t.Errorf("%d: bad args: %v", i, args)
+		}
+	}
+}
+
+func TestSelectBindIgnoresLiteralsAndCasts(t *testing.T) {
+	query, args, _ := Select().
+		Dialect(Postgres).
+		From("customers").
+		Map("id", nil).
+		Where("", "note <> ':none' AND kind = :kind::text -- :comment").
+		Bind(map[string]interface{}{"kind": "a"}).
+		Build()
+
+	expectedQuery := "SELECT id\n FROM customers\n WHERE (note <> ':none' AND kind = $1::text -- :comment)"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{"a"}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestSelectBindMissing(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "sqlbuilder: no value bound for :id" {
+			t.Errorf("bad panic: %v", r)
+		}
+	}()
+
+	Select().From("customers").Map("id", nil).Where("id", "= :id").Bind(nil).Build()
+}
+
+func TestUpdateBindStruct(t *testing.T) {
+	type base struct {
+		ID int `db:"id"`
+	}
+	type params struct {
+		base
+		Name    string `db:"name"`
+		Ignored string `db:"-"`
+		Age     int
+	}
+
+	query, args := Update().
+		Dialect(Postgres).
+		Table("customers").
+		Set("name", "John").
+		Where("id", "= :id").
+		Where("", "name <> :name AND age < :Age").
+		BindStruct(&params{base{9}, "Jon", "x", 21}).
+		Build()
+
+	expectedQuery := "UPDATE customers SET name = $1\n WHERE (id = $2) AND (name <> $3 AND age < $4)"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{"John", 9, "Jon", 21}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestDeleteBind(t *testing.T) {
+	query, args := Delete().
+		Dialect(Oracle).
+		From("customers").
+		Where("", "id = :id OR parent_id = :id").
+		Bind(map[string]interface{}{"id": 9}).
+		Build()
+
+	expectedQuery := "DELETE FROM customers\n WHERE (id = :1 OR parent_id = :2)"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{9, 9}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestSelectBindWithSliceArgs(t *testing.T) {
+	query, args, _ := Select().
+		Dialect(Postgres).
+		From("t").
+		Map("id", nil).
+		Where("id", "IN (?,?) AND x = :x AND y = ?", []int{1, 2}, 5).
+		Bind(map[string]interface{}{"x": "X"}).
+		Build()
+
+	expectedQuery := "SELECT id\n FROM t\n WHERE (id IN ($1,$2) AND x = $3 AND y = $4)"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{1, 2, "X", 5}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestSelectBindNamedWithClashingColumn(t *testing.T) {
+	query, args, _ := Select().
+		Dialect(SQLServer.Named()).
+		From("t").
+		Map("id", nil).
+		Where("id", "= ?", 5).
+		Where("x", "= :id OR y = :id").
+		Where("z", "= ?", 7).
+		Bind(map[string]interface{}{"id": 9}).
+		Build()
+
+	expectedQuery := "SELECT id\n FROM t\n WHERE (id = @id) AND (x = @id_2 OR y = @id_2) AND (z = @z)"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{sql.Named("id", 5), sql.Named("id_2", 9), sql.Named("z", 7)}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
@@ -0,0 +1,67 @@
+package sqlbuilder
+
+import (
+	"database/sql"
+	"reflect"
+	"sync"
+)
+
+var registry = struct {
+	sync.RWMutex
+	dialects map[string]Dialect
+}{
+	dialects: map[string]Dialect{
+		"mysql":     MySQL,
+		"postgres":  Postgres,
+		"pgx":       Postgres,
+		"sqlite3":   SQLite,
+		"sqlite":    SQLite,
+		"sqlserver": SQLServer,
+		"mssql":     SQLServer,
+		"godror":    Oracle,
+		"oracle":    Oracle,
+	},
+}
+
+// RegisterDialect sets the dialect used for the database/sql driver named 'driverName',
+// replacing any existing entry. The drivers for MySQL, Postgres (postgres and pgx),
+// SQLite (sqlite3 and sqlite), SQL Server (sqlserver and mssql) and Oracle (godror and
+// oracle) are registered already.
+func RegisterDialect(driverName string, dialect Dialect) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.dialects[driverName] = dialect
+}
+
+// DialectFor returns the dialect registered for the database/sql driver named 'driverName'.
+func DialectFor(driverName string) (Dialect, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	dialect, ok := registry.dialects[driverName]
+	return dialect, ok
+}
+
+// DialectForDB returns the dialect registered for the driver used by 'db'. As *sql.DB does
+// not record the name it was opened with, this finds the driver names registered with
+// database/sql whose driver has the same type.
+func DialectForDB(db *sql.DB) (Dialect, bool) {
+	t := reflect.TypeOf(db.Driver())
+	for _, driverName := range sql.Drivers() {
+		dialect, ok := DialectFor(driverName)
+		if ok && driverType(driverName) == t {
+			return dialect, true
+		}
+	}
+	return nil, false
+}
+
+// driverType gets the type of the database/sql driver named 'driverName'. Opening a
+// database does not connect to it, so this does not need a valid data source.
+func driverType(driverName string) reflect.Type {
+	db, err := sql.Open(driverName, "")
+	if err != nil {
+		return nil
+	}
+	defer db.Close()
+	return reflect.TypeOf(db.Driver())
+}
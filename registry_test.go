@@ -0,0 +1,67 @@
+package sqlbuilder
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+type testDriver struct{}
+
+func (testDriver) Open(name string) (driver.Conn, error) {
+	return nil, errors.New("not implemented")
+}
+
+func init() {
+	sql.Register("sqlbuilder-test", testDriver{})
+}
+
+func TestDialectFor(t *testing.T) {
+	cases := []struct {
+		driverName string
+		expected   Dialect
+	}{
+		{"mysql", MySQL},
+		{"postgres", Postgres},
+		{"pgx", Postgres},
+		{"sqlite3", SQLite},
+		{"sqlite", SQLite},
+		{"sqlserver", SQLServer},
+	}
+
+	for _, c := range cases {
+		dialect, ok := DialectFor(c.driverName)
+		if !ok || dialect != c.expected {
+			t.Errorf("%s: bad dialect: %T", c.driverName, dialect)
+		}
+	}
+
+	if _, ok := DialectFor("unknown"); ok {
+		t.Errorf("expected no dialect")
+	}
+}
+
+func TestDialectForDB(t *testing.T) {
+	db, err := sql.Open("sqlbuilder-test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, ok := DialectForDB(db); ok {
+		t.Errorf("expected no dialect")
+	}
+
+	RegisterDialect("sqlbuilder-test", SQLServer)
+	t.Cleanup(func() {
+		registry.Lock()
+		defer registry.Unlock()
+		delete(registry.dialects, "sqlbuilder-test")
+	})
+
+	dialect, ok := DialectForDB(db)
+	if !ok || dialect != SQLServer {
+		t.Errorf("bad dialect: %T", dialect)
+	}
+}
@@ -0,0 +1,233 @@
+package sqlbuilder
+
+import "strings"
+
+// TableResolver maps the table names used in statements to those in the database, e.g. so
+// that the same statements can be used with differently-named tables in each environment.
+// It is given the schema, which may be "", and the name of each table and returns the
+// schema and name to use instead.
+type TableResolver func(schema, table string) (string, string)
+
+// DefaultTableResolver is used by statements that do not set a resolver with ResolveTables.
+// If nil, table names are used as given.
+var DefaultTableResolver TableResolver
+
+// NewTableResolver returns a resolver that adds 'prefix' to every table name and renames
+// the schemas in 'schemas'. Other schemas are not changed.
+// For example NewTableResolver("stg_", map[string]string{"sales": "sales_staging"})
+func NewTableResolver(prefix string, schemas map[string]string) TableResolver {
+	return func(schema, table string) (string, string) {
+		if s, ok := schemas[schema]; ok {
+			schema = s
+		}
+		if isQuotedIdentifier(table) {
+			return schema, table[:1] + prefix + table[1:]
+		}
+		return schema, prefix + table
+	}
+}
+
+// tableRefs maps the ways in which columns may be qualified by a table, e.g. "customers"
+// and "sales.customers", to the resolved table.
+type tableRefs map[string]string
+
+// resolve returns the name as given by the resolver 'r', and records how references to
+// it change. Aliased tables are referred to by their aliases, which do not change.
+func (refs tableRefs) resolve(n name, r TableResolver) name {
+	if r == nil || n.name == "" {
+		return n
+	}
+
+	schema, table := r(n.schema, n.name)
+	if n.alias == "" {
+		refs[n.name] = table
+		if n.schema != "" {
+			refs[n.schema+"."+n.name] = schema + "." + table
+		}
+	}
+	n.schema, n.name = schema, table
+	return n
+}
+
+// rewrite changes the tables qualifying any column references in the SQL text 'sql', such
+// as "customers.id" in "customers.id = ?", if they refer to resolved tables. String literals
+// and comments are not changed.
+func (refs tableRefs) rewrite(sql string) string {
+	if len(refs) == 0 {
+		return sql
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == '\'':
+			j := skipQuoted(sql, i, c)
+			b.WriteString(sql[i:j])
+			i = j
+
+		case isCommentStart(sql, i):
+			j := skipComment(sql, i)
+			b.WriteString(sql[i:j])
+			i = j
+
+		case isReferenceStart(c) && (i == 0 || !isReferenceContinuation(sql[i-1])):
+			j := skipReference(sql, i)
+			b.WriteString(refs.reference(sql[i:j]))
+			i = j
+
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+// reference changes the table qualifying the dotted reference 'ref', if it is resolved.
+func (refs tableRefs) reference(ref string) string {
+	parts := splitName(ref)
+	i := len(parts) - 1
+	if i == 0 {
+		return ref
+	}
+
+	if table, ok := refs[strings.Join(parts[:i], ".")]; ok {
+		return table + "." + parts[i]
+	}
+	return ref
+}
+
+// skipReference returns the index just after the dotted reference starting at sql[i],
+// whose parts may be quoted, e.g. sales."order items".id
+func skipReference(sql string, i int) int {
+	for {
+		switch sql[i] {
+		case '"', '`':
+			i = skipQuoted(sql, i, sql[i])
+		case '[':
+			if j := strings.IndexByte(sql[i:], ']'); j > 0 {
+				i += j + 1
+			} else {
+				i = len(sql)
+			}
+		default:
+			for i < len(sql) && (isIdentifierByte(sql[i]) || sql[i] == '$') {
+				i++
+			}
+		}
+
+		if i+1 >= len(sql) || sql[i] != '.' || !(isReferenceStart(sql[i+1]) || sql[i+1] == '*') {
+			return i
+		}
+		i++
+		if sql[i] == '*' {
+			return i + 1
+		}
+	}
+}
+
+func isReferenceStart(c byte) bool {
+	return isMarkerStart(c) || c == '"' || c == '`' || c == '['
+}
+
+// isReferenceContinuation is true for characters that, preceding a name, show that it is
+// not the start of a reference, e.g. the ':' of a named marker or a cast.
+func isReferenceContinuation(c byte) bool {
+	return isIdentifierByte(c) || c == '.' || c == ':' || c == '@' || c == '$'
+}
+
+// tables changes the table names 'names', which may also be aliases or, for Oracle,
+// qualified columns, where they refer to resolved tables.
+func (refs tableRefs) tables(names []string) []string {
+	if len(refs) == 0 {
+		return names
+	}
+	resolved := make([]string, len(names))
+	for i, n := range names {
+		if table, ok := refs[n]; ok {
+			resolved[i] = table
+		} else {
+			resolved[i] = refs.rewrite(n)
+		}
+	}
+	return resolved
+}
+
+func (refs tableRefs) columns(cols []string) []string {
+	if len(refs) == 0 {
+		return cols
+	}
+	resolved := make([]string, len(cols))
+	for i, c := range cols {
+		resolved[i] = refs.rewrite(c)
+	}
+	return resolved
+}
+
+func (refs tableRefs) order(orders []order) []order {
+	if len(refs) == 0 {
+		return orders
+	}
+	resolved := make([]order, len(orders))
+	for i, o := range orders {
+		o.col = refs.rewrite(o.col)
+		resolved[i] = o
+	}
+	return resolved
+}
+
+func (refs tableRefs) window(w Window) Window {
+	w.partition = refs.columns(w.partition)
+	w.order = refs.order(w.order)
+	return w
+}
+
+func (refs tableRefs) conditions(conds []where) []where {
+	if len(refs) == 0 {
+		return conds
+	}
+	resolved := make([]where, len(conds))
+	for i, w := range conds {
+		w.col = refs.rewrite(w.col)
+		w.sql = refs.rewrite(w.sql)
+		resolved[i] = w
+	}
+	return resolved
+}
+
+func resolverOrDefault(r TableResolver) TableResolver {
+	if r != nil {
+		return r
+	}
+	return DefaultTableResolver
+}
+
+// ResolveTables returns a new statement in which table names are mapped by 'r', instead
+// of by DefaultTableResolver. Column names qualified by a table, rather than its alias,
+// are changed to match, including those within conditions and expressions.
+func (s SelectStatement) ResolveTables(r TableResolver) SelectStatement {
+	s.resolver = r
+	return s
+}
+
+// ResolveTables returns a new statement in which the table name is mapped by 'r', instead
+// of by DefaultTableResolver.
+func (s InsertStatement) ResolveTables(r TableResolver) InsertStatement {
+	s.resolver = r
+	return s
+}
+
+// ResolveTables returns a new statement in which the table name is mapped by 'r', instead
+// of by DefaultTableResolver. Column names qualified by the table are changed to match.
+func (s UpdateStatement) ResolveTables(r TableResolver) UpdateStatement {
+	s.resolver = r
+	return s
+}
+
+// ResolveTables returns a new statement in which the table name is mapped by 'r', instead
+// of by DefaultTableResolver. Column names qualified by the table are changed to match.
+func (s DeleteStatement) ResolveTables(r TableResolver) DeleteStatement {
+	s.resolver = r
+	return s
+}
@@ -0,0 +1,160 @@
+package sqlbuilder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelectResolveTables(t *testing.T) {
+	r := NewTableResolver("stg_", map[string]string{"sales": "sales_staging"})
+
+	var total int
+	query, _, _ := Select().
+		Dialect(Postgres).
+		ResolveTables(r).
+		From("customers").
+		Join("sales.orders").As("o").On("o.customer_id", "customers.id").
+		Join("sales.notes").On("sales.notes.customer_id", "customers.id").
+		Map("customers.name", nil).
+		Sum("o.total", &total).
+		Where("customers.active", "= ?", true).
+		GroupBy("customers.name").
+		OrderBy("customers.name").
+		Build()
+
+	expected := `SELECT stg_customers.name, SUM(o.total)
+ FROM stg_customers
+ JOIN sales_staging.stg_orders AS o ON o.customer_id = stg_customers.id
+ JOIN sales_staging.stg_notes ON sales_staging.stg_notes.customer_id = stg_customers.id
+ WHERE (stg_customers.active = $1)
+ GROUP BY stg_customers.name
+ ORDER BY stg_customers.name`
+	if query != expected {
+		t.Errorf("bad query: %q", query)
+	}
+}
+
+func TestSelectResolveTablesLockOf(t *testing.T) {
+	r := NewTableResolver("stg_", nil)
+
+	query, _, _ := Select().
+		Dialect(Postgres).
+		ResolveTables(r).
+		From("jobs").
+		Map("jobs.id", nil).
+		Lock().LockOf("jobs").SkipLocked().
+		Limit(1).
+		Build()
+
+	expected := "SELECT stg_jobs.id\n FROM stg_jobs\n LIMIT 1\n FOR UPDATE OF stg_jobs SKIP LOCKED"
+	if query != expected {
+		t.Errorf("bad query: %q", query)
+	}
+
+	query, _, _ = Select().
+		Dialect(SQLServer).
+		ResolveTables(r).
+		From("jobs").
+		Join("workers").As("w").On("w.id", "jobs.worker_id").
+		Map("jobs.id", nil).
+		Lock().LockOf("jobs").SkipLocked().
+		Build()
+
+	expected = "SELECT stg_jobs.id\n FROM stg_jobs WITH (UPDLOCK, ROWLOCK, READPAST)\n JOIN stg_workers AS w ON w.id = stg_jobs.worker_id"
+	if query != expected {
+		t.Errorf("bad query: %q", query)
+	}
+}
+
+func TestDefaultTableResolver(t *testing.T) {
+	DefaultTableResolver = NewTableResolver("", map[string]string{"": "test"})
+	defer func() { DefaultTableResolver = nil }()
+
+	query, _, _ := Insert().Dialect(Postgres).Into("customers").Set("name", "John").Build()
+	if query != "INSERT INTO test.customers (name) VALUES ($1)" {
+		t.Errorf("bad query: %q", query)
+	}
+
+	query, args := Update().Dialect(Postgres).Table("customers").Set("name", "John").Where("customers.id", "= ?", 9).Build()
+	if query != "UPDATE test.customers SET name = $1\n WHERE (customers.id = $2)" {
+		t.Errorf("bad query: %q", query)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"John", 9}) {
+		t.Errorf("bad args: %v", args)
+	}
+
+	query, _ = Delete().Dialect(Postgres).ResolveTables(NewTableResolver("stg_", nil)).From("customers").Where("customers.id", "= ?", 9).Build()
+	if query != "DELETE FROM stg_customers\n WHERE (stg_customers.id = $1)" {
+		t.Errorf("bad query: %q", query)
+	}
+}
+
+func TestUpdateResolveTablesInSets(t *testing.T) {
+	query, args := Update().
+		Dialect(Postgres).
+		ResolveTables(NewTableResolver("stg_", nil)).
+		Table("orders").
+		Set("orders.total", 1).
+		SetSQL("orders.updated", "orders.created").
+		Where("orders.id", "= ?", 9).
+		Build()
+
+	expected := "UPDATE stg_orders SET stg_orders.total = $1, stg_orders.updated = stg_orders.created\n WHERE (stg_orders.id = $2)"
+	if query != expected {
+		t.Errorf("bad query: %q", query)
+	}
+	if !reflect.DeepEqual(args, []interface{}{1, 9}) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestSelectResolveTablesDistinctOnAndWindows(t *testing.T) {
+	query, _, _ := Select().
+		Dialect(Postgres).
+		ResolveTables(NewTableResolver("stg_", nil)).
+		From("customers").
+		DistinctOn("customers.id").
+		Map("customers.id", nil).
+		MapOver("RANK()", Over().PartitionBy("customers.city").OrderBy("customers.age"), nil).
+		Window("w", Over().PartitionBy("customers.region")).
+		OrderBy("customers.id").
+		Build()
+
+	expected := `SELECT DISTINCT ON (stg_customers.id) stg_customers.id, RANK() OVER (PARTITION BY stg_customers.city ORDER BY stg_customers.age)
+ FROM stg_customers
+ WINDOW w AS (PARTITION BY stg_customers.region)
+ ORDER BY stg_customers.id`
+	if query != expected {
+		t.Errorf("bad query: %q", query)
+	}
+}
+
+func TestSelectResolveTablesInConditions(t *testing.T) {
+	query, args, _ := Select().
+		Dialect(Postgres).
+		ResolveTables(NewTableResolver("stg_", nil)).
+		From("customers").
+		Join("orders").OnSQL("orders.customer_id = customers.id").
+		AndOn("orders.status <> 'customers.x' AND orders.total::numeric > ?", 10).
+		Map("customers.id", nil).
+		Where("", "customers.city = :city -- customers.city").
+		GroupBy("customers.id").
+		Having("COUNT(orders.id) > ?", 1).
+		Bind(map[string]interface{}{"city": "Paris"}).
+		Build()
+
+	expected := `SELECT stg_customers.id
+ FROM stg_customers
+ JOIN stg_orders ON (stg_orders.customer_id = stg_customers.id) AND (stg_orders.status <> 'customers.x' AND stg_orders.total::numeric > $1)
+ WHERE (stg_customers.city = $2 -- customers.city)
+ GROUP BY stg_customers.id
+ HAVING (COUNT(stg_orders.id) > $3)`
+	if query != expected {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{10, "Paris", 1}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
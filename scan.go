@@ -0,0 +1,105 @@
+package sqlbuilder
+
+import "strings"
+
+// Rebind rewrites the '?' placeholders in 'query' into the style of 'dialect', e.g. $1, $2
+// for Postgres. Any '?' within a string literal, quoted identifier or comment is left
+// unchanged. This allows hand-written SQL to be used with any dialect.
+func Rebind(dialect Dialect, query string) string {
+	return RebindFrom(dialect, query, 0)
+}
+
+// RebindFrom is like Rebind but numbers the placeholders from index 'idx', e.g. so that
+// the query can follow another with 'idx' parameters.
+func RebindFrom(dialect Dialect, query string, idx int) string {
+	return replaceMarkers(query, func(marker string) string {
+		if marker != "?" {
+			return marker
+		}
+		p := dialect.Placeholder(idx)
+		idx++
+		return p
+	})
+}
+
+// replaceMarkers calls 'fn' for each parameter marker in 'sql', replacing the marker with
+// the result. Markers are '?' and named markers such as ':id'; the marker is passed to
+// 'fn' including its colon. Markers within string literals, quoted identifiers and
+// comments are ignored, as are Postgres '::' casts.
+func replaceMarkers(sql string, fn func(marker string) string) string {
+	var b strings.Builder
+	b.Grow(len(sql))
+
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			j := skipQuoted(sql, i, c)
+			b.WriteString(sql[i:j])
+			i = j
+
+		case isCommentStart(sql, i):
+			j := skipComment(sql, i)
+			b.WriteString(sql[i:j])
+			i = j
+
+		case c == '?':
+			b.WriteString(fn("?"))
+			i++
+
+		case c == ':' && i+1 < len(sql) && sql[i+1] == ':':
+			b.WriteString("::")
+			i += 2
+
+		case c == ':' && i+1 < len(sql) && isMarkerStart(sql[i+1]) && (i == 0 || !isIdentifierByte(sql[i-1])):
+			j := i + 1
+			for j < len(sql) && isIdentifierByte(sql[j]) {
+				j++
+			}
+			b.WriteString(fn(sql[i:j]))
+			i = j
+
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+// skipQuoted returns the index just after the quoted text starting at sql[i], where the
+// quote character 'q' is escaped by doubling it.
+func skipQuoted(sql string, i int, q byte) int {
+	for j := i + 1; j < len(sql); j++ {
+		if sql[j] == q {
+			if j+1 < len(sql) && sql[j+1] == q {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(sql)
+}
+
+func isCommentStart(sql string, i int) bool {
+	return strings.HasPrefix(sql[i:], "--") || strings.HasPrefix(sql[i:], "/*")
+}
+
+// skipComment returns the index just after the comment starting at sql[i].
+func skipComment(sql string, i int) int {
+	if strings.HasPrefix(sql[i:], "--") {
+		if j := strings.IndexByte(sql[i:], '\n'); j >= 0 {
+			return i + j
+		}
+		return len(sql)
+	}
+	if j := strings.Index(sql[i+2:], "*/"); j >= 0 {
+		return i + 2 + j + 2
+	}
+	return len(sql)
+}
+
+func isMarkerStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
@@ -0,0 +1,31 @@
+package sqlbuilder
+
+import "testing"
+
+func TestRebind(t *testing.T) {
+	query := "SELECT id FROM customers WHERE name = ? AND note <> 'why?' /* or? */ AND \"a?\" = ? -- ?\n AND id IN (?, ?)"
+
+	cases := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{MySQL, query},
+		{Postgres, "SELECT id FROM customers WHERE name = $1 AND note <> 'why?' /* or? */ AND \"a?\" = $2 -- ?\n AND id IN ($3, $4)"},
+		{SQLServer, "SELECT id FROM customers WHERE name = @p1 AND note <> 'why?' /* or? */ AND \"a?\" = @p2 -- ?\n AND id IN (@p3, @p4)"},
+		{Oracle, "SELECT id FROM customers WHERE name = :1 AND note <> 'why?' /* or? */ AND \"a?\" = :2 -- ?\n AND id IN (:3, :4)"},
+	}
+
+	for i, c := range cases {
+		if actual := Rebind(c.dialect, query); actual != c.expected {
+			t.Errorf("%d: bad query: %q", i, actual)
+		}
+	}
+}
+
+func TestRebindFrom(t *testing.T) {
+	actual := RebindFrom(Postgres, "name = ? AND note = 'it''s?' AND kind = ?::text", 2)
+	expected := "name = $3 AND note = 'it''s?' AND kind = $4::text"
+	if actual != expected {
+		t.Errorf("bad query: %q", actual)
+	}
+}
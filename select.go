@@ -1,8 +1,8 @@
 package sqlbuilder
 
 import (
+	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 )
 
@@ -15,39 +15,115 @@ func Select() SelectStatement {
 
 // SelectStatement represents a SELECT statement.
 type SelectStatement struct {
-	dialect  Dialect
-	distinct string
-	last     lastWas
-	table    name
-	columns  []column
-	joinNat  string
-	joinOp   string
-	joinTbl  name
-	joins    []join
-	wheres   []where
-	lock     bool
-	limit    *int
-	offset   *int
-	order    []order
-	group    string
-	having   string
+	dialect    Dialect
+	schema     string
+	distinct   string
+	distinctOn []string
+	last       lastWas
+	table      name
+	columns    []column
+	joinNat    string
+	joinOp     string
+	joinTbl    name
+	joins      []join
+	wheres     []where
+	lock       *lock
+	limit      *int
+	offset     *int
+	withTies   bool
+	order      []order
+	group      []grouping
+	having     []where
+	windows    []namedWindow
+	seek       []interface{}
+	params     params
+	resolver   TableResolver
 }
 
 type column struct {
 	col  name
 	dest interface{}
+	over *Window
+	agg  *aggregate
 }
 
+// build renders the column. Any arguments it needs are appended to 'args'.
+func (c column) build(args []interface{}, idx int, dialect Dialect) (string, []interface{}, int) {
+	sql := c.col.name
+	if c.agg != nil {
+		sql, args, idx = c.agg.build(args, idx, dialect)
+	}
+	if c.over != nil {
+		sql += " OVER " + c.over.build(dialect)
+	}
+	if c.col.alias == "" {
+		return sql, args, idx
+	}
+	return sql + " AS " + c.col.alias, args, idx
+}
+
+// Direction is the sort direction of an ORDER BY column.
+type Direction bool
+
+const (
+	Ascending  Direction = false // smallest first
+	Descending Direction = true  // largest first
+)
+
 type order struct {
-	col  string
-	desc bool
+	col     string
+	args    []interface{}
+	desc    bool
+	nulls   string
+	collate string
 }
 
-func (o order) build(dialect Dialect) string {
+// build renders the ordering column. Any arguments it needs are appended to 'args'.
+// Where the dialect has no NULLS FIRST/LAST, an extra IS NULL ordering is used instead.
+func (o order) build(args []interface{}, idx int, dialect Dialect) (string, []interface{}, int) {
+	var sql, isNull string
+	nulls := o.nulls
+	if nulls != "" && dialect.Features().Has(FeatureNullsOrdering) {
+		// supported natively
+	} else if nulls != "" && (isMySQL(dialect) || isSQLite(dialect)) {
+		isNull, args, idx = where{"", o.col, o.args}.build(args, idx, dialect)
+		if nulls == "FIRST" {
+			isNull += " IS NULL DESC, "
+		} else {
+			isNull += " IS NULL, "
+		}
+		nulls = ""
+	} else if nulls != "" {
+		isNull, args, idx = where{"", o.col, o.args}.build(args, idx, dialect)
+		if nulls == "FIRST" {
+			isNull = "CASE WHEN " + isNull + " IS NULL THEN 0 ELSE 1 END, "
+		} else {
+			isNull = "CASE WHEN " + isNull + " IS NULL THEN 1 ELSE 0 END, "
+		}
+		nulls = ""
+	}
+
+	sql, args, idx = where{"", o.col, o.args}.build(args, idx, dialect)
+	sql = isNull + sql
+
+	if o.collate != "" {
+		sql += " COLLATE " + o.collate
+	}
 	if o.desc {
-		return o.col + " DESC"
+		sql += " DESC"
+	}
+	if nulls != "" {
+		sql += " NULLS " + nulls
 	}
-	return o.col
+	return sql, args, idx
+}
+
+func buildOrderBy(args []interface{}, idx int, orders []order, dialect Dialect) (string, []interface{}, int) {
+	quoted := make([]string, len(orders))
+	for i, o := range orders {
+		quoted[i], args, idx = o.build(args, idx, dialect)
+	}
+	return strings.Join(quoted, ", "), args, idx
 }
 
 // Dialect returns a new statement with dialect set to 'dialect'.
@@ -56,15 +132,32 @@ func (s SelectStatement) Dialect(dialect Dialect) SelectStatement {
 	return s
 }
 
+// Schema returns a new statement in which table names that have no schema are qualified
+// by 'schema'. This overrides any default schema of the dialect.
+func (s SelectStatement) Schema(schema string) SelectStatement {
+	s.schema = schema
+	return s
+}
+
 // Distinct modifies the select to remove duplicates from the results.
 func (s SelectStatement) Distinct() SelectStatement {
 	s.distinct = "DISTINCT "
 	return s
 }
 
+// DistinctOn modifies the select to keep only the first row of each set of rows where the
+// columns 'col' are equal. The ORDER BY must start with the same columns, and it determines
+// which row is first. For example, DistinctOn("customer_id").OrderBy("customer_id", "created").Desc()
+// selects the latest row per customer. Only Postgres supports this.
+func (s SelectStatement) DistinctOn(col ...string) SelectStatement {
+	s.distinct = "DISTINCT "
+	s.distinctOn = append(s.distinctOn[:len(s.distinctOn):len(s.distinctOn)], col...)
+	return s
+}
+
 // From returns a new statement with the table to select from set to 'table'.
 func (s SelectStatement) From(table string) SelectStatement {
-	s.table = name{table, ""}
+	s.table = tableName(table)
 	s.last = lastWasTableName
 	return s
 }
@@ -73,7 +166,7 @@ func (s SelectStatement) From(table string) SelectStatement {
 func (s SelectStatement) Columns(col ...string) SelectStatement {
 	dest := nullDest
 	for _, c := range col {
-		s.columns = append(s.columns, column{name{c, ""}, dest})
+		s.columns = append(s.columns, column{col: name{name: c}, dest: dest})
 	}
 	s.last = lastWasColumnName
 	return s
@@ -85,18 +178,42 @@ func (s SelectStatement) Map(col string, dest interface{}) SelectStatement {
 	if dest == nil {
 		dest = nullDest
 	}
-	s.columns = append(s.columns, column{name{col, ""}, dest})
+	s.columns = append(s.columns, column{col: name{name: col}, dest: dest})
+	s.last = lastWasColumnName
+	return s
+}
+
+// MapOver returns a new statement with the window function 'fn' over window 'w' selected
+// and scanned into 'dest'. 'dest' may be nil if the value should not be scanned.
+// For example MapOver("ROW_NUMBER()", Over().PartitionBy("city").OrderBy("age"), &n).As("rn")
+func (s SelectStatement) MapOver(fn string, w Window, dest interface{}) SelectStatement {
+	if dest == nil {
+		dest = nullDest
+	}
+	s.columns = append(s.columns, column{col: name{name: fn}, dest: dest, over: &w})
 	s.last = lastWasColumnName
 	return s
 }
 
+// Window returns a new statement with a named window, which is referred to using OverWindow.
+// Multiple Window() calls can be used.
+func (s SelectStatement) Window(name string, w Window) SelectStatement {
+	s.windows = append(s.windows, namedWindow{name, w})
+	return s
+}
+
 // As modifies the preceding table or column name by setting an alias.
 func (s SelectStatement) As(alias string) SelectStatement {
 	switch s.last {
 	case lastWasTableName:
-		s.table = name{s.table.name, alias}
+		s.table.alias = alias
 	case lastWasJoinTableName:
-		s.joinTbl = name{s.joinTbl.name, alias}
+		s.joinTbl.alias = alias
+	case lastWasLateralJoin:
+		i := len(s.joins) - 1
+		j := s.joins[i]
+		j.table.alias = alias
+		s.joins = append(s.joins[:i:i], j)
 	case lastWasColumnName:
 		i := len(s.columns) - 1
 		s.columns[i].col.alias = alias
@@ -122,65 +239,351 @@ func (s SelectStatement) WhereEq(col string, args ...interface{}) SelectStatemen
 }
 
 // Limit returns a new statement with the limit set to 'limit'.
-// This works with MySQL and SqLite, but is unlikely to work with other dbms.
+// The dialect determines how this is rendered, e.g. LIMIT or FETCH FIRST.
 func (s SelectStatement) Limit(limit int) SelectStatement {
 	s.limit = &limit
 	return s
 }
 
 // Offset returns a new statement with the offset set to 'offset'.
-// This works with MySQL and SqLite, but is unlikely to work with other dbms.
+// The dialect determines how this is rendered, e.g. OFFSET or OFFSET ... ROWS.
 func (s SelectStatement) Offset(offset int) SelectStatement {
 	s.offset = &offset
 	return s
 }
 
+// WithTies modifies the limit so that any further rows that tie with the last row, according
+// to the ORDER BY, are also returned. MySQL does not support this.
+func (s SelectStatement) WithTies() SelectStatement {
+	s.withTies = true
+	return s
+}
+
 // OrderBy returns a new statement with ordering 'order', which may be a list of column names.
 // Multiple OrderBy() calls can be used.
 func (s SelectStatement) OrderBy(column ...string) SelectStatement {
 	for _, c := range column {
-		s.order = append(s.order, order{c, false})
+		s.order = append(s.order, order{col: c})
+	}
+	return s
+}
+
+// OrderByDir returns a new statement with ordering 'column', which may be a list of column
+// names, each sorted in direction 'dir'. Multiple OrderBy() calls can be used.
+func (s SelectStatement) OrderByDir(dir Direction, column ...string) SelectStatement {
+	for _, c := range column {
+		s.order = append(s.order, order{col: c, desc: bool(dir)})
 	}
 	return s
 }
 
+// OrderByExpr returns a new statement with ordering by the expression 'expr' and the
+// necessary arguments to that expression. For example OrderByExpr("FIELD(status, ?, ?)", "new", "paid")
+// Multiple OrderBy() calls can be used.
+func (s SelectStatement) OrderByExpr(expr string, args ...interface{}) SelectStatement {
+	s.order = append(s.order, order{col: expr, args: args})
+	return s
+}
+
 // Desc reverses the sort order of the last ordering column specified with OrderBy(). Only
 // the last column is reversed; any earlier ones rmain unchanged.
 // This panics if there hasn't been an OrderBy yet.
 func (s SelectStatement) Desc() SelectStatement {
-	s.order[len(s.order)-1].desc = true
-	return s
+	return s.modifyOrder(func(o *order) { o.desc = true })
 }
 
-// GroupBy returns a new statement with grouping 'group'.
-// Only the last GroupBy() is used.
-func (s SelectStatement) GroupBy(group string) SelectStatement {
-	s.group = group
-	return s
+// NullsFirst modifies the last ordering column so that nulls sort before other values.
+// Where the dialect lacks FeatureNullsOrdering, an equivalent IS NULL ordering is used.
+// This panics if there hasn't been an OrderBy yet.
+func (s SelectStatement) NullsFirst() SelectStatement {
+	return s.modifyOrder(func(o *order) { o.nulls = "FIRST" })
+}
+
+// NullsLast modifies the last ordering column so that nulls sort after other values.
+// Where the dialect lacks FeatureNullsOrdering, an equivalent IS NULL ordering is used.
+// This panics if there hasn't been an OrderBy yet.
+func (s SelectStatement) NullsLast() SelectStatement {
+	return s.modifyOrder(func(o *order) { o.nulls = "LAST" })
+}
+
+// Collate modifies the last ordering column so that it is sorted using 'collation'.
+// This panics if there hasn't been an OrderBy yet.
+func (s SelectStatement) Collate(collation string) SelectStatement {
+	return s.modifyOrder(func(o *order) { o.collate = collation })
 }
 
-// Having returns a new statement with HAVING condition 'having'.
-// Only the last Having() is used.
-func (s SelectStatement) Having(having string) SelectStatement {
-	s.having = having
+func (s SelectStatement) modifyOrder(fn func(*order)) SelectStatement {
+	i := len(s.order) - 1
+	o := s.order[i]
+	fn(&o)
+	s.order = append(s.order[:i:i], o)
 	return s
 }
 
-// Lock returns a new statement with FOR UPDATE locking.
-func (s SelectStatement) Lock() SelectStatement {
-	s.lock = true
+// Having returns a new statement with a HAVING condition and the necessary arguments
+// to that condition. For example Having("COUNT(*) > ?", 5)
+//
+// Multiple having-clauses are combined with AND.
+func (s SelectStatement) Having(cond string, args ...interface{}) SelectStatement {
+	s.having = append(s.having, where{"", cond, args})
 	return s
 }
 
 // Build builds the SQL query. It returns the query, the argument slice,
 // and the destination slice.
+//
+// Build panics if the statement uses features that its dialect does not support;
+// use Validate to check this first.
 func (s SelectStatement) Build() (query string, args []interface{}, dest []interface{}) {
+	if err := s.Validate(); err != nil {
+		panic(err)
+	}
+	query, args, dest, _ = s.build(nil, 0)
+	args = unwrapParams(args)
+	return
+}
+
+// Validate checks that the dialect supports all the features used by the statement.
+// If not, it returns an *UnsupportedError. It also checks that the clauses of the
+// statement can be used together, e.g. that ORDER BY starts with the DISTINCT ON columns.
+func (s SelectStatement) Validate() error {
+	if err := checkFeatures(s.dialect, s.features()); err != nil {
+		return err
+	}
+	return s.checkClauses()
+}
+
+// checkClauses checks for combinations of clauses that cannot be rendered, including
+// within any subqueries.
+func (s SelectStatement) checkClauses() error {
+	if s.withTies && s.limit != nil {
+		if len(s.order) == 0 {
+			return errors.New("sqlbuilder: WITH TIES requires ORDER BY")
+		}
+		if s.offset != nil && isSQLServer(s.dialect) {
+			return errors.New("sqlbuilder: WITH TIES with an offset is not supported by SQL Server")
+		}
+	}
+
+	if len(s.seek) > 0 {
+		if err := s.checkSeek(); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range s.columns {
+		if c.over != nil {
+			if err := c.over.check(); err != nil {
+				return err
+			}
+		}
+	}
+	for _, nw := range s.windows {
+		if err := nw.window.check(); err != nil {
+			return err
+		}
+	}
+
+	if err := checkGroupBy(s.group, s.dialect); err != nil {
+		return err
+	}
+
+	if d, ok := s.dialect.(OracleDialect); ok && d.usesRownum() && s.lock != nil && (s.limit != nil || s.offset != nil) {
+		// the ROWNUM subquery cannot be locked (ORA-02014)
+		return errors.New("sqlbuilder: locking with LIMIT or OFFSET is not supported by Oracle before 12c")
+	}
+
+	if len(s.distinctOn) > 0 && len(s.order) > 0 {
+		on := make(map[string]bool)
+		for _, c := range s.distinctOn {
+			on[c] = true
+		}
+		for i := 0; i < len(s.distinctOn); i++ {
+			if i >= len(s.order) || !on[s.order[i].col] {
+				return errors.New("sqlbuilder: ORDER BY must start with the DISTINCT ON columns")
+			}
+		}
+	}
+
+	for _, j := range s.joins {
+		if j.lateral != nil {
+			if j.table.alias == "" {
+				return errors.New("sqlbuilder: LATERAL join without an alias")
+			}
+			if err := j.lateral.Dialect(s.dialect).checkClauses(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// features lists the optional features that the statement uses.
+func (s SelectStatement) features() []Feature {
+	var used []Feature
+
+	if len(s.distinctOn) > 0 {
+		used = append(used, FeatureDistinctOn)
+	}
+
+	for _, c := range s.columns {
+		if c.over != nil {
+			used = append(used, FeatureWindowFunctions)
+		}
+	}
+	if len(s.windows) > 0 {
+		used = append(used, FeatureWindowFunctions)
+	}
+
+	for _, j := range s.joins {
+		used = append(used, j.features(s.dialect)...)
+	}
+
+	for _, g := range s.group {
+		used = append(used, g.features()...)
+	}
+
+	if s.withTies && s.limit != nil {
+		used = append(used, FeatureWithTies)
+	}
+
+	if s.lock != nil {
+		used = append(used, s.lock.features(s.dialect)...)
+	}
+
+	return used
+}
+
+// BuildCount builds a query that counts all the rows the statement would return, e.g. for
+// pagination. The columns, ordering, seek, limit, offset and locking are ignored; the joins,
+// where-clauses, grouping and having are kept. If the statement is DISTINCT or grouped,
+// it is wrapped in a subquery so that distinct rows or groups are counted.
+// It returns the query, the argument slice, and the destination slice containing 'count'.
+func (s SelectStatement) BuildCount(count *int) (query string, args []interface{}, dest []interface{}) {
+	s.order = nil
+	s.seek = nil
+	s.limit = nil
+	s.offset = nil
+	s.withTies = false
+	s.lock = nil
+
+	if err := s.Validate(); err != nil {
+		panic(err)
+	}
+
+	if s.distinct == "" && len(s.group) == 0 {
+		s.columns = nil
+		s.windows = nil
+		return s.Count(count).Build()
+	}
+
+	if s.distinct == "" {
+		s.columns = nil
+		s.windows = nil
+	}
+
+	query, args, _, _ = s.build(nil, 0)
+	args = unwrapParams(args)
+	query = "SELECT COUNT(*)\n FROM (" + query + ")" + tableAs(s.dialect) + "t"
+	dest = []interface{}{count}
+	return
+}
+
+// resolveTables applies the default schema and the table resolver to the table names,
+// and changes any column names that refer to them. A subquery uses the same resolver
+// unless it has its own.
+func (s SelectStatement) resolveTables() SelectStatement {
+	r := resolverOrDefault(s.resolver)
+	refs := make(tableRefs)
+
+	s.table = refs.resolve(s.table.qualify(s.schema, s.dialect), r)
+
+	joins := make([]join, len(s.joins))
+	for i, j := range s.joins {
+		j.table = refs.resolve(j.table.qualify(s.schema, s.dialect), r)
+		if j.lateral != nil && j.lateral.resolver == nil {
+			sub := j.lateral.ResolveTables(r)
+			j.lateral = &sub
+		}
+		joins[i] = j
+	}
+	s.joins = joins
+
+	if len(refs) == 0 {
+		return s
+	}
+
+	columns := make([]column, len(s.columns))
+	for i, c := range s.columns {
+		c.col.name = refs.rewrite(c.col.name)
+		if c.agg != nil {
+			a := *c.agg
+			a.col = refs.rewrite(a.col)
+			c.agg = &a
+		}
+		if c.over != nil {
+			w := refs.window(*c.over)
+			c.over = &w
+		}
+		columns[i] = c
+	}
+	s.columns = columns
+
+	windows := make([]namedWindow, len(s.windows))
+	for i, nw := range s.windows {
+		windows[i] = namedWindow{nw.name, refs.window(nw.window)}
+	}
+	s.windows = windows
+
+	s.distinctOn = refs.columns(s.distinctOn)
+
+	if s.lock != nil {
+		l := *s.lock
+		l.of = refs.tables(l.of)
+		s.lock = &l
+	}
+
+	for i, j := range s.joins {
+		j.onL = refs.rewrite(j.onL)
+		j.onR = refs.rewrite(j.onR)
+		j.on = refs.conditions(j.on)
+		s.joins[i] = j
+	}
+
+	s.wheres = refs.conditions(s.wheres)
+	s.having = refs.conditions(s.having)
+
+	s.order = refs.order(s.order)
+
+	group := make([]grouping, len(s.group))
+	for i, g := range s.group {
+		g.cols = refs.columns(g.cols)
+		sets := make([][]string, len(g.sets))
+		for k, set := range g.sets {
+			sets[k] = refs.columns(set)
+		}
+		g.sets = sets
+		group[i] = g
+	}
+	s.group = group
+	return s
+}
+
+// build builds the SQL query with placeholders numbered from 'idx'. This allows the
+// statement to be used as a subquery of another statement.
+func (s SelectStatement) build(args []interface{}, idx int) (string, []interface{}, []interface{}, int) {
+	s = s.bindParams()
+	s = s.resolveTables()
+
+	var query string
 	var cols []string
-	idx := 0
+	var dest []interface{}
 
 	if len(s.columns) > 0 {
 		for _, sel := range s.columns {
-			cols = append(cols, sel.col.String())
+			var col string
+			col, args, idx = sel.build(args, idx, s.dialect)
+			cols = append(cols, col)
 			if sel.dest == nil {
 				dest = append(dest, &nullDest)
 			} else {
@@ -192,46 +595,81 @@ func (s SelectStatement) Build() (query string, args []interface{}, dest []inter
 		dest = append(dest, &nullDest)
 	}
 
-	query = fmt.Sprintf("SELECT %s%s\n FROM %s",
-		s.distinct,
+	query = fmt.Sprintf("SELECT %s%s\n FROM %s%s",
+		s.buildDistinct(),
 		strings.Join(cols, ", "),
-		s.table)
+		s.table.QuotedAs(s.dialect),
+		s.tableHint(s.table))
 
 	for _, join := range s.joins {
-		query += join.String()
+		var sql string
+		sql, args, idx = join.build(args, idx, s.dialect, s.tableHint(join.table))
+		query += sql
 	}
 
-	if len(s.wheres) > 0 {
-		query, args, idx = buildWhereClause(query, args, idx, s.wheres, s.dialect)
+	wheres := s.wheres
+	if len(s.seek) > 0 {
+		wheres = append(wheres[:len(wheres):len(wheres)], s.seekCondition())
 	}
 
-	if len(s.order) > 0 {
-		quoted := make([]string, len(s.order))
-		for i, o := range s.order {
-			quoted[i] = o.build(s.dialect)
+	if len(wheres) > 0 {
+		query, args, idx = buildWhereClause(query, args, idx, wheres, s.dialect)
+	}
+
+	if len(s.group) > 0 {
+		query += buildGroupBy(s.group, s.dialect)
+	}
+
+	if len(s.having) > 0 {
+		query, args, idx = buildConditionClause(query, "HAVING", args, idx, s.having, s.dialect)
+	}
+
+	if len(s.windows) > 0 {
+		var defs []string
+		for _, w := range s.windows {
+			defs = append(defs, w.name+" AS ("+w.window.buildSpec(s.dialect)+")")
 		}
-		query += "\n ORDER BY " + strings.Join(quoted, ", ")
+		query += "\n WINDOW " + strings.Join(defs, ", ")
 	}
 
-	if s.group != "" {
-		query += "\n GROUP BY " + s.group
+	if len(s.order) > 0 {
+		var orderBy string
+		orderBy, args, idx = buildOrderBy(args, idx, s.order, s.dialect)
+		query += "\n ORDER BY " + orderBy
 	}
 
-	if s.having != "" {
-		query += "\n HAVING " + s.having
+	if s.limit != nil || s.offset != nil {
+		query = s.dialect.Paginate(query, Pagination{
+			Limit:    s.limit,
+			Offset:   s.offset,
+			WithTies: s.withTies,
+			Ordered:  len(s.order) > 0,
+		})
+
+		if oracle, ok := s.dialect.(OracleDialect); ok && oracle.usesRownum() && s.offset != nil {
+			dest = append(dest, &nullDest) // the ROWNUM column
+		}
 	}
 
-	if s.limit != nil {
-		query += "\n LIMIT " + strconv.Itoa(*s.limit)
+	if s.lock != nil {
+		query += s.lock.build(s.dialect)
 	}
 
-	if s.offset != nil {
-		query += "\n OFFSET " + strconv.Itoa(*s.offset)
+	return query, args, dest, idx
+}
+
+// tableHint renders the table hint needed for locking table 't', if any.
+func (s SelectStatement) tableHint(t name) string {
+	if s.lock == nil {
+		return ""
 	}
+	return s.lock.tableHint(t, s.dialect)
+}
 
-	if s.lock {
-		query += "\n FOR UPDATE"
+func (s SelectStatement) buildDistinct() string {
+	if len(s.distinctOn) == 0 {
+		return s.distinct
 	}
 
-	return
+	return "DISTINCT ON (" + strings.Join(s.distinctOn, ", ") + ") "
 }
@@ -1,6 +1,7 @@
 package sqlbuilder
 
 import (
+	"database/sql"
 	"reflect"
 	"testing"
 )
@@ -191,6 +192,31 @@ func TestSelectWithGroupMySQL(t *testing.T) {
 	}
 }
 
+func TestSelectWithGroupAndOrderMySQL(t *testing.T) {
+	var city string
+	var count uint
+	query, args, _ := Select().
+		Dialect(MySQL).
+		From("customers").
+		Map("city", &city).
+		Map("COUNT(*)", &count).
+		OrderBy("city").
+		GroupBy("city").
+		Having("COUNT(*) > ?", 1).
+		Limit(10).
+		Build()
+
+	expectedQuery := "SELECT city, COUNT(*)\n FROM customers\n GROUP BY city\n HAVING (COUNT(*) > ?)\n ORDER BY city\n LIMIT 10"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{1}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
 func TestSelectWithWherePostgres(t *testing.T) {
 	c := customer{}
 
@@ -247,3 +273,1043 @@ func TestSelectWithInClauseUsingSlice(t *testing.T) {
 		t.Errorf("bad args: %v", args)
 	}
 }
+
+func TestSelectWithInClauseUsingSliceSQLServer(t *testing.T) {
+	c := customer{}
+
+	input := []int{4, 5, 6}
+	query, args, _ := Select().
+		Dialect(SQLServer).
+		From("customers").
+		Map("id", &c.ID).
+		Map("name", &c.Name).
+		Where("name", "IS NOT NULL").
+		Where("id", "in (?,?,?)", input).
+		Where("age", "BETWEEN ? AND ?", 10, 20).
+		Build()
+
+	expectedQuery := `SELECT id, name
+ FROM customers
+ WHERE (name IS NOT NULL) AND (id in (@p1,@p2,@p3)) AND (age BETWEEN @p4 AND @p5)`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{4, 5, 6, 10, 20}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestSelectWithJoinConditionsPostgres(t *testing.T) {
+	c := customer{}
+
+	query, args, _ := Select().
+		Dialect(Postgres).
+		From("customers").As("c").
+		Map("c.id", &c.ID).
+		Map("c.name", &c.Name).
+		Inner().Join("orders").As("o").On("o.customer_id", "c.id").AndOn("o.status = ?", "paid").
+		Left().Join("refunds").As("r").OnSQL("r.order_id = o.id").AndOn("r.created > c.since").
+		Left().Join("notes").As("n").OnSQL("n.kind IN (?,?)", []string{"a", "b"}).
+		Where("c.age", "BETWEEN ? AND ?", 10, 20).
+		Build()
+
+	expectedQuery := `SELECT c.id, c.name
+ FROM customers AS c
+ INNER JOIN orders AS o ON (o.customer_id = c.id) AND (o.status = $1)
+ LEFT JOIN refunds AS r ON (r.order_id = o.id) AND (r.created > c.since)
+ LEFT JOIN notes AS n ON n.kind IN ($2,$3)
+ WHERE (c.age BETWEEN $4 AND $5)`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{"paid", "a", "b", 10, 20}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestSelectWithLateralJoinPostgres(t *testing.T) {
+	c := customer{}
+	var total int
+
+	latest := Select().
+		From("orders").
+		Map("total", nil).
+		Where("customer_id", "= c.id").
+		Where("status", "= ?", "paid").
+		OrderBy("created").Desc().
+		Limit(3)
+
+	query, args, _ := Select().
+		Dialect(Postgres).
+		From("customers").As("c").
+		Map("c.id", &c.ID).
+		Map("o.total", &total).
+		Left().JoinLateral(latest).As("o").
+		Where("c.age", "> ?", 21).
+		Build()
+
+	expectedQuery := `SELECT c.id, o.total
+ FROM customers AS c
+ LEFT JOIN LATERAL (SELECT total
+ FROM orders
+ WHERE (customer_id = c.id) AND (status = $1)
+ ORDER BY created DESC
+ LIMIT 3) o ON true
+ WHERE (c.age > $2)`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{"paid", 21}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestSelectWithCrossLateralJoin(t *testing.T) {
+	latest := Select().
+		From("orders").
+		Map("total", nil).
+		Where("customer_id", "= c.id").
+		Limit(1)
+
+	cases := []struct {
+		dialect  Dialect
+		expected string
+	}{
+		{Postgres, "SELECT c.id\n FROM customers AS c\n CROSS JOIN LATERAL (SELECT total\n FROM orders\n WHERE (customer_id = c.id)\n LIMIT 1) o"},
+		{MySQL, "SELECT c.id\n FROM customers AS c\n CROSS JOIN LATERAL (SELECT total\n FROM orders\n WHERE (customer_id = c.id)\n LIMIT 1) o"},
+		{SQLServer, "SELECT c.id\n FROM customers AS c\n CROSS APPLY (SELECT TOP (1) total\n FROM orders\n WHERE (customer_id = c.id)) o"},
+	}
+
+	for i, c := range cases {
+		query, _, _ := Select().
+			Dialect(c.dialect).
+			From("customers").As("c").
+			Map("c.id", nil).
+			Cross().JoinLateral(latest).As("o").
+			Build()
+		if query != c.expected {
+			t.Errorf("%d: bad query: %q", i, query)
+		}
+	}
+
+	err := Select().Dialect(Postgres).From("customers").As("c").JoinLateral(latest).Validate()
+	if err == nil {
+		t.Errorf("expected an error for a LATERAL join without an alias")
+	}
+}
+
+func TestSelectWithWindowFunctions(t *testing.T) {
+	c := customer{}
+	var rank, running int
+
+	query, _, dest := Select().
+		Dialect(Postgres).
+		From("customers").
+		Map("id", &c.ID).
+		MapOver("RANK()", Over().PartitionBy("city").OrderBy("age").Desc(), &rank).As("rnk").
+		MapOver("SUM(age)", OverWindow("w").Rows(UnboundedPreceding, CurrentRow), &running).
+		MapOver("COUNT(*)", OverWindow("w"), nil).
+		MapOver("AVG(age)", Over().OrderBy("id").Rows(Preceding(2), Following(1)), nil).
+		Window("w", Over().PartitionBy("city").OrderBy("id")).
+		OrderBy("id").
+		Build()
+
+	expectedQuery := `SELECT id, RANK() OVER (PARTITION BY city ORDER BY age DESC) AS rnk,` +
+		` SUM(age) OVER (w ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW),` +
+		` COUNT(*) OVER w,` +
+		` AVG(age) OVER (ORDER BY id ROWS BETWEEN 2 PRECEDING AND 1 FOLLOWING)
+ FROM customers
+ WINDOW w AS (PARTITION BY city ORDER BY id)
+ ORDER BY id`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedDest := []interface{}{&c.ID, &rank, &running, &nullDest, &nullDest}
+	if !reflect.DeepEqual(dest, expectedDest) {
+		t.Errorf("bad dest: %v", dest)
+	}
+
+	cases := []SelectStatement{
+		Select().MapOver("SUM(age)", OverWindow("w").PartitionBy("city"), nil).Window("w", Over().OrderBy("id")),
+		Select().Window("w", Over().OrderBy("id")).Window("v", OverWindow("w").PartitionBy("city")),
+	}
+
+	for i, c := range cases {
+		err := c.Dialect(Postgres).From("customers").Validate()
+		if err == nil || err.Error() != "sqlbuilder: window w cannot be partitioned again" {
+			t.Errorf("%d: bad error: %v", i, err)
+		}
+	}
+}
+
+func TestSelectWithAggregatesPostgres(t *testing.T) {
+	var n, cities, paid, big int
+	var avg float64
+	var youngest, oldest int
+
+	query, args, dest := Select().
+		Dialect(Postgres).
+		From("customers").As("c").
+		Count(&n).As("n").
+		CountDistinct("c.city", &cities).
+		Sum("o.total", &paid).Filter("o.status = ?", "paid").
+		Count(&big).Filter("o.total > ?", 100).As("big").
+		Avg("order", &avg).
+		Min("age", &youngest).
+		Max("age", &oldest).
+		Inner().Join("orders").As("o").On("o.customer_id", "c.id").
+		Where("c.age", "> ?", 21).
+		Build()
+
+	expectedQuery := `SELECT COUNT(*) AS n, COUNT(DISTINCT c.city),` +
+		` SUM(o.total) FILTER (WHERE o.status = $1),` +
+		` COUNT(*) FILTER (WHERE o.total > $2) AS big,` +
+		` AVG("order"), MIN(age), MAX(age)
+ FROM customers AS c
+ INNER JOIN orders AS o ON o.customer_id = c.id
+ WHERE (c.age > $3)`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{"paid", 100, 21}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+
+	expectedDest := []interface{}{&n, &cities, &paid, &big, &avg, &youngest, &oldest}
+	if !reflect.DeepEqual(dest, expectedDest) {
+		t.Errorf("bad dest: %v", dest)
+	}
+}
+
+func TestSelectWithFilteredAggregatesMySQL(t *testing.T) {
+	var paid, big, cities int
+
+	query, args, _ := Select().
+		Dialect(MySQL).
+		From("orders").
+		Sum("total", &paid).Filter("status = ?", "paid").
+		Count(&big).Filter("total > ?", 100).
+		CountDistinct("key", &cities).Filter("total > ?", 10).
+		Build()
+
+	expectedQuery := "SELECT SUM(CASE WHEN status = ? THEN total END)," +
+		" COUNT(CASE WHEN total > ? THEN 1 END)," +
+		" COUNT(DISTINCT CASE WHEN total > ? THEN `key` END)\n FROM orders"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{"paid", 100, 10}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestSelectWithAggregatedExpressions(t *testing.T) {
+	var amount, average, orders float64
+
+	query, _, _ := Select().
+		Dialect(MySQL).
+		From("orders").As("o").
+		Sum("o.price * o.qty", &amount).
+		Avg("price * qty", &average).
+		Sum("o.order", &orders).
+		Sum("total-discount", &amount).
+		Build()
+
+	expectedQuery := "SELECT SUM(o.price * o.qty), AVG(price * qty), SUM(o.`order`), SUM(total-discount)\n FROM orders AS o"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+}
+
+func TestSelectBuildCount(t *testing.T) {
+	c := customer{}
+	var n int
+
+	page := Select().
+		Dialect(Postgres).
+		From("customers").As("c").
+		Map("c.id", &c.ID).
+		Map("c.name", &c.Name).
+		Inner().Join("orders").As("o").On("o.customer_id", "c.id").AndOn("o.status = ?", "paid").
+		Where("c.age", "> ?", 21).
+		OrderBy("c.name").
+		Limit(10).
+		Offset(20).
+		Lock()
+
+	query, args, dest := page.BuildCount(&n)
+
+	expectedQuery := `SELECT COUNT(*)
+ FROM customers AS c
+ INNER JOIN orders AS o ON (o.customer_id = c.id) AND (o.status = $1)
+ WHERE (c.age > $2)`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{"paid", 21}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+
+	expectedDest := []interface{}{&n}
+	if !reflect.DeepEqual(dest, expectedDest) {
+		t.Errorf("bad dest: %v", dest)
+	}
+}
+
+func TestSelectBuildCountIgnoresLocking(t *testing.T) {
+	var n int
+
+	cases := []SelectStatement{
+		Select().Dialect(SQLite.RejectLocks()).From("t").Lock(),
+		Select().Dialect(Oracle.Version(11)).From("t").Lock().Limit(10),
+	}
+
+	for i, c := range cases {
+		query, _, _ := c.BuildCount(&n)
+		if query != "SELECT COUNT(*)\n FROM t" {
+			t.Errorf("%d: bad query: %q", i, query)
+		}
+	}
+}
+
+func TestSelectBuildCountWithDistinctAndGroup(t *testing.T) {
+	var city string
+	var n int
+
+	query, args, dest := Select().
+		Dialect(MySQL).Distinct().
+		From("customers").
+		Map("city", &city).
+		Where("age", "> ?", 21).
+		OrderBy("city").
+		Limit(10).
+		BuildCount(&n)
+
+	expectedQuery := "SELECT COUNT(*)\n FROM (SELECT DISTINCT city\n FROM customers\n WHERE (age > ?)) AS t"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{21}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+
+	expectedDest := []interface{}{&n}
+	if !reflect.DeepEqual(dest, expectedDest) {
+		t.Errorf("bad dest: %v", dest)
+	}
+
+	query, _, _ = Select().
+		Dialect(MySQL).
+		From("customers").
+		Map("city", &city).
+		Count(nil).
+		GroupBy("city").
+		Having("COUNT(*) > 1").
+		BuildCount(&n)
+
+	expectedQuery = "SELECT COUNT(*)\n FROM (SELECT 1\n FROM customers\n GROUP BY city\n HAVING (COUNT(*) > 1)) AS t"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+}
+
+func TestSelectSeekAfterWithRowValues(t *testing.T) {
+	c := customer{}
+
+	query, args, _ := Select().
+		Dialect(Postgres).
+		From("customers").
+		Map("id", &c.ID).
+		Map("name", &c.Name).
+		Where("age", "> ?", 21).
+		OrderBy("name", "id").
+		SeekAfter(20, "Smith", 42).
+		Build()
+
+	expectedQuery := `SELECT id, name
+ FROM customers
+ WHERE (age > $1) AND ((name, id) > ($2, $3))
+ ORDER BY name, id
+ LIMIT 20`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{21, "Smith", 42}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestSelectSeekAfterWithMixedOrder(t *testing.T) {
+	c := customer{}
+
+	query, args, _ := Select().
+		Dialect(MySQL).
+		From("customers").
+		Map("id", &c.ID).
+		OrderBy("age").Desc().OrderBy("name", "id").
+		SeekAfter(20, 30, "Smith", 42).
+		Build()
+
+	expectedQuery := "SELECT id\n FROM customers\n" +
+		" WHERE ((age < ?) OR (age = ? AND name > ?) OR (age = ? AND name = ? AND id > ?))\n" +
+		" ORDER BY age DESC, name, id\n LIMIT 20"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{30, 30, "Smith", 30, "Smith", 42}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+
+	query, args, _ = Select().
+		Dialect(MySQL).
+		From("customers").
+		Map("id", &c.ID).
+		OrderBy("id").Desc().
+		SeekAfter(20, 42).
+		Build()
+
+	expectedQuery = "SELECT id\n FROM customers\n WHERE (id < ?)\n ORDER BY id DESC\n LIMIT 20"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs = []interface{}{42}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestSelectSeekAfterErrors(t *testing.T) {
+	cases := []SelectStatement{
+		Select().OrderBy("a", "b").SeekAfter(20, 1),
+		Select().OrderByExpr("a + ?", 1).SeekAfter(20, 2),
+	}
+
+	for i, c := range cases {
+		if err := c.Dialect(Postgres).From("t").Validate(); err == nil {
+			t.Errorf("%d: expected an error", i)
+		}
+	}
+}
+
+func TestSelectWithLockOptionsPostgres(t *testing.T) {
+	var id int
+
+	query, _, _ := Select().
+		Dialect(Postgres).
+		From("jobs").As("j").
+		Map("j.id", &id).
+		Inner().Join("queues").As("q").On("q.id", "j.queue_id").
+		Where("j.state", "= 'ready'").
+		OrderBy("j.id").
+		Limit(1).
+		Lock().LockOf("j").SkipLocked().
+		Build()
+
+	expectedQuery := `SELECT j.id
+ FROM jobs AS j
+ INNER JOIN queues AS q ON q.id = j.queue_id
+ WHERE (j.state = 'ready')
+ ORDER BY j.id
+ LIMIT 1
+ FOR UPDATE OF j SKIP LOCKED`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	cases := []struct {
+		s        SelectStatement
+		expected string
+	}{
+		{Select().Dialect(Postgres).From("t").LockShare().NoWait(), "SELECT 1\n FROM t\n FOR SHARE NOWAIT"},
+		{Select().Dialect(Postgres).From("t").LockNoKeyUpdate(), "SELECT 1\n FROM t\n FOR NO KEY UPDATE"},
+		{Select().Dialect(Postgres).From("t").LockKeyShare().LockOf("t", "u"), "SELECT 1\n FROM t\n FOR KEY SHARE OF t, u"},
+		{Select().Dialect(MySQL).From("t").LockShare().SkipLocked(), "SELECT 1\n FROM t\n FOR SHARE SKIP LOCKED"},
+	}
+
+	for i, c := range cases {
+		query, _, _ := c.s.Build()
+		if query != c.expected {
+			t.Errorf("%d: bad query: %q", i, query)
+		}
+	}
+}
+
+func TestSelectWithUnsupportedLockMySQL(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected a panic")
+		}
+	}()
+
+	Select().Dialect(MySQL).From("t").LockKeyShare().Build()
+}
+
+func TestSelectDistinctOnPostgres(t *testing.T) {
+	var customerID, total int
+
+	query, _, _ := Select().
+		Dialect(Postgres).
+		DistinctOn("customer_id").
+		From("orders").
+		Map("customer_id", &customerID).
+		Map("total", &total).
+		OrderBy("customer_id", "created").Desc().
+		Build()
+
+	expectedQuery := `SELECT DISTINCT ON (customer_id) customer_id, total
+ FROM orders
+ ORDER BY customer_id, created DESC`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+}
+
+func TestSelectDistinctOnRejected(t *testing.T) {
+	cases := []SelectStatement{
+		Select().Dialect(MySQL).DistinctOn("a").From("t"),
+		Select().Dialect(Postgres).DistinctOn("a").From("t").OrderBy("b", "a"),
+		Select().Dialect(Postgres).DistinctOn("a", "b").From("t").OrderBy("a"),
+	}
+
+	for i, c := range cases {
+		if c.Validate() == nil {
+			t.Errorf("%d: expected an error", i)
+		}
+
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%d: expected a panic", i)
+				}
+			}()
+			c.Build()
+		}()
+	}
+
+	var n int
+	err := Select().Dialect(Postgres).DistinctOn("a").From("t").OrderBy("b").Validate()
+	if err == nil || err.Error() != "sqlbuilder: ORDER BY must start with the DISTINCT ON columns" {
+		t.Errorf("bad error: %v", err)
+	}
+
+	// the ordering is ignored when counting
+	query, _, _ := Select().Dialect(Postgres).DistinctOn("a").From("t").OrderBy("b").BuildCount(&n)
+	if query != "SELECT COUNT(*)\n FROM (SELECT DISTINCT ON (a) 1\n FROM t) AS t" {
+		t.Errorf("bad query: %q", query)
+	}
+}
+
+func TestSelectWithRichOrderPostgres(t *testing.T) {
+	c := customer{}
+
+	query, args, _ := Select().
+		Dialect(Postgres).
+		From("customers").
+		Map("id", &c.ID).
+		Where("age", "> ?", 21).
+		OrderByExpr("CASE WHEN status = ? THEN 0 ELSE 1 END", "vip").
+		OrderByDir(Descending, "phone").NullsLast().
+		OrderBy("name").Collate(`"C"`).
+		OrderByDir(Ascending, "id").
+		Build()
+
+	expectedQuery := `SELECT id
+ FROM customers
+ WHERE (age > $1)
+ ORDER BY CASE WHEN status = $2 THEN 0 ELSE 1 END, phone DESC NULLS LAST, name COLLATE "C", id`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{21, "vip"}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestSelectWithRichOrderMySQL(t *testing.T) {
+	c := customer{}
+
+	query, args, _ := Select().
+		Dialect(MySQL).
+		From("customers").
+		Map("id", &c.ID).
+		OrderByExpr("FIELD(status, ?, ?)", "new", "paid").NullsFirst().
+		OrderByDir(Descending, "phone").NullsLast().
+		Build()
+
+	expectedQuery := "SELECT id\n FROM customers\n" +
+		" ORDER BY FIELD(status, ?, ?) IS NULL DESC, FIELD(status, ?, ?), phone IS NULL, phone DESC"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{"new", "paid", "new", "paid"}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestSelectWithGroupingPostgres(t *testing.T) {
+	var total int
+
+	cases := []struct {
+		s        SelectStatement
+		expected string
+	}{
+		{Select().GroupBy("country").GroupBy("city", "date_trunc('month', created)"),
+			"GROUP BY country, city, date_trunc('month', created)"},
+		{Select().GroupBy().GroupBy("country").GroupByRollup(),
+			"GROUP BY country"},
+		{Select().GroupBy("country").GroupByRollup("city", "street"),
+			"GROUP BY country, ROLLUP (city, street)"},
+		{Select().GroupByCube("country", "city"),
+			"GROUP BY CUBE (country, city)"},
+		{Select().GroupByGroupingSets([]string{"country", "city"}, []string{"country"}, nil),
+			"GROUP BY GROUPING SETS ((country, city), (country), ())"},
+	}
+
+	for i, c := range cases {
+		query, _, _ := c.s.Dialect(Postgres).From("orders").Sum("total", &total).Build()
+		expectedQuery := "SELECT SUM(total)\n FROM orders\n " + c.expected
+		if query != expectedQuery {
+			t.Errorf("%d: bad query: %q", i, query)
+		}
+	}
+}
+
+func TestSelectWithRollupMySQL(t *testing.T) {
+	var total int
+
+	query, _, _ := Select().
+		Dialect(MySQL).From("orders").Sum("total", &total).GroupByRollup("country", "city").Build()
+	expectedQuery := "SELECT SUM(total)\n FROM orders\n GROUP BY country, city WITH ROLLUP"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	cases := []SelectStatement{
+		Select().GroupBy("country").GroupByRollup("city"),
+		Select().GroupByCube("country"),
+		Select().GroupByGroupingSets([]string{"country"}),
+	}
+
+	for i, c := range cases {
+		if err := c.Dialect(MySQL).From("orders").Validate(); err == nil {
+			t.Errorf("%d: expected an error", i)
+		}
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%d: expected a panic", i)
+				}
+			}()
+			c.Dialect(MySQL).From("orders").Build()
+		}()
+	}
+}
+
+func TestSelectWithHavingPostgres(t *testing.T) {
+	var city string
+	var n int
+
+	query, args, _ := Select().
+		Dialect(Postgres).
+		From("customers").
+		Map("city", &city).
+		Count(&n).
+		Where("age", "> ?", 21).
+		GroupBy("city").
+		Having("COUNT(*) > ?", 5).
+		Having("MAX(age) BETWEEN ? AND ?", 30, 40).
+		OrderByExpr("COUNT(*) > ?", 10).Desc().
+		Build()
+
+	expectedQuery := `SELECT city, COUNT(*)
+ FROM customers
+ WHERE (age > $1)
+ GROUP BY city
+ HAVING (COUNT(*) > $2) AND (MAX(age) BETWEEN $3 AND $4)
+ ORDER BY COUNT(*) > $5 DESC`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{21, 5, 30, 40, 10}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestSelectPagination(t *testing.T) {
+	cases := []struct {
+		s        SelectStatement
+		expected string
+	}{
+		{Select().Dialect(MySQL).Limit(5), "\n LIMIT 5"},
+		{Select().Dialect(MySQL).Offset(10), "\n LIMIT 18446744073709551615\n OFFSET 10"},
+		{Select().Dialect(Postgres).Offset(10), "\n OFFSET 10"},
+		{Select().Dialect(Postgres).Limit(5).Offset(10), "\n LIMIT 5\n OFFSET 10"},
+		{Select().Dialect(Postgres).OrderBy("id").Limit(5).WithTies(), "\n ORDER BY id\n FETCH FIRST 5 ROWS WITH TIES"},
+		{Select().Dialect(Postgres).OrderBy("id").Limit(5).Offset(10).WithTies(),
+			"\n ORDER BY id\n OFFSET 10 ROWS\n FETCH FIRST 5 ROWS WITH TIES"},
+	}
+
+	for i, c := range cases {
+		query, _, _ := c.s.From("customers").Build()
+		expectedQuery := "SELECT 1\n FROM customers" + c.expected
+		if query != expectedQuery {
+			t.Errorf("%d: bad query: %q", i, query)
+		}
+	}
+}
+
+func TestSelectWithTiesRejected(t *testing.T) {
+	cases := []SelectStatement{
+		Select().Dialect(MySQL).OrderBy("id").Limit(5).WithTies(),
+		Select().Dialect(Postgres).Limit(5).WithTies(),
+		Select().Dialect(SQLServer).Limit(5).WithTies(),
+		Select().Dialect(SQLServer).OrderBy("id").Limit(5).Offset(10).WithTies(),
+	}
+
+	for i, c := range cases {
+		if c.From("customers").Validate() == nil {
+			t.Errorf("%d: expected an error", i)
+		}
+
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%d: expected a panic", i)
+				}
+			}()
+			c.From("customers").Build()
+		}()
+	}
+}
+
+func TestSelectLockWithLimitOracle11Rejected(t *testing.T) {
+	err := Select().Dialect(Oracle.Version(11)).From("jobs").Lock().Limit(1).Validate()
+	if err == nil || err.Error() != "sqlbuilder: locking with LIMIT or OFFSET is not supported by Oracle before 12c" {
+		t.Errorf("bad error: %v", err)
+	}
+
+	if err := Select().Dialect(Oracle.Version(11)).From("jobs").Lock().Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := Select().Dialect(Oracle).From("jobs").Lock().Limit(1).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestSelectWithWhereSQLServer(t *testing.T) {
+	c := customer{}
+
+	query, args, _ := Select().
+		Dialect(SQLServer).
+		From("customers").As("c").
+		Map("c.id", &c.ID).
+		Map("c.name", &c.Name).
+		Map("c.telephone", &c.Phone).As("phone").
+		Map("c.age", &c.Age).
+		Cross().Join("orders").As("o").On("o.customer_id", "c.id").
+		Where("c.id", "= ?", 9).
+		Where("c.name", "IS NOT NULL").
+		Where("c.age", "BETWEEN ? AND ?", 10, 20).
+		Build()
+
+	expectedQuery := `SELECT c.id, c.name, c.telephone AS phone, c.age
+ FROM customers AS c
+ CROSS JOIN orders AS o ON o.customer_id = c.id
+ WHERE (c.id = @p1) AND (c.name IS NOT NULL) AND (c.age BETWEEN @p2 AND @p3)`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{9, 10, 20}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestSelectWithNamedParametersSQLServer(t *testing.T) {
+	c := customer{}
+
+	query, args, _ := Select().
+		Dialect(SQLServer.Named()).
+		From("customers").As("c").
+		Map("c.id", &c.ID).
+		Where("c.id", "IN (?, ?)", []int{1, 2}).
+		Where("c.age", "BETWEEN ? AND ?", 10, 20).
+		Where("", "c.name = ? OR c.phone = ?", "John", sql.Named("tel", "555")).
+		Build()
+
+	expectedQuery := `SELECT c.id
+ FROM customers AS c
+ WHERE (c.id IN (@id, @id_2)) AND (c.age BETWEEN @age AND @age_2) AND (c.name = @name OR c.phone = @tel)`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{sql.Named("id", 1), sql.Named("id_2", 2), sql.Named("age", 10),
+		sql.Named("age_2", 20), sql.Named("name", "John"), sql.Named("tel", "555")}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestSelectWithSchemas(t *testing.T) {
+	cases := []struct {
+		statement SelectStatement
+		expected  string
+	}{
+		{Select().Dialect(Postgres).From("sales.orders").As("o").
+			Join("public.customers").As("c").On("o.customer_id", "c.id"),
+			"SELECT 1\n FROM sales.orders AS o\n JOIN public.customers AS c ON o.customer_id = c.id"},
+		{Select().Dialect(SQLServer).From("shop.dbo.orders").
+			Join("shop.dbo.customers").On("shop.dbo.orders.customer_id", "shop.dbo.customers.id"),
+			"SELECT 1\n FROM shop.dbo.orders\n JOIN shop.dbo.customers ON shop.dbo.orders.customer_id = shop.dbo.customers.id"},
+		{Select().Dialect(SQLServer).From("[my.db].dbo.[Order Details]").As("d"),
+			"SELECT 1\n FROM [my.db].dbo.[Order Details] AS d"},
+		{Select().Dialect(Postgres).From("t").Join("u").On("t.id", "u.t_id::int"),
+			"SELECT 1\n FROM t\n JOIN u ON t.id = u.t_id::int"},
+		{Select().Dialect(MySQL).From("a").Join("b").On("a.x", "b.y + 1"),
+			"SELECT 1\n FROM a\n JOIN b ON a.x = b.y + 1"},
+		{Select().Dialect(MySQL).From("a").Join("b").On("a.user", "b.key"),
+			"SELECT 1\n FROM a\n JOIN b ON a.`user` = b.`key`"},
+		{Select().Dialect(MySQL).From("t").Join("u").On("t.a", "u.b-1"),
+			"SELECT 1\n FROM t\n JOIN u ON t.a = u.b-1"},
+		{Select().Dialect(Postgres).From("my-schema.orders"),
+			"SELECT 1\n FROM \"my-schema\".orders"},
+		{Select().Dialect(Postgres).Schema("sales").From("orders").As("o").
+			Join("public.customers").As("c").On("o.customer_id", "c.id"),
+			"SELECT 1\n FROM sales.orders AS o\n JOIN public.customers AS c ON o.customer_id = c.id"},
+		{Select().Dialect(SQLServer.Schema("dbo")).From("orders").Join("customers").On("orders.customer_id", "customers.id"),
+			"SELECT 1\n FROM dbo.orders\n JOIN dbo.customers ON orders.customer_id = customers.id"},
+		{Select().Dialect(SQLServer.Schema("dbo")).Schema("audit").From("orders"),
+			"SELECT 1\n FROM audit.orders"},
+	}
+
+	for i, c := range cases {
+		query, _, _ := c.statement.Build()
+		if query != c.expected {
+			t.Errorf("%d: bad query: %q", i, query)
+		}
+	}
+}
+
+func TestSelectPaginationSQLServer(t *testing.T) {
+	cases := []struct {
+		s        SelectStatement
+		expected string
+	}{
+		{Select().Limit(5),
+			"SELECT TOP (5) 1\n FROM customers"},
+		{Select().Distinct().OrderBy("id").Limit(5).WithTies(),
+			"SELECT DISTINCT TOP (5) WITH TIES 1\n FROM customers\n ORDER BY id"},
+		{Select().OrderBy("id").Limit(5),
+			"SELECT 1\n FROM customers\n ORDER BY id\n OFFSET 0 ROWS\n FETCH NEXT 5 ROWS ONLY"},
+		{Select().OrderBy("id").Limit(5).Offset(10),
+			"SELECT 1\n FROM customers\n ORDER BY id\n OFFSET 10 ROWS\n FETCH NEXT 5 ROWS ONLY"},
+		{Select().Offset(10),
+			"SELECT 1\n FROM customers\n ORDER BY (SELECT NULL)\n OFFSET 10 ROWS"},
+		{Select().OrderBy("phone").NullsLast(),
+			"SELECT 1\n FROM customers\n ORDER BY CASE WHEN phone IS NULL THEN 1 ELSE 0 END, phone"},
+	}
+
+	for i, c := range cases {
+		query, _, _ := c.s.Dialect(SQLServer).From("customers").Build()
+		if query != c.expected {
+			t.Errorf("%d: bad query: %q", i, query)
+		}
+	}
+}
+
+func TestSelectWithLockAndApplySQLServer(t *testing.T) {
+	var id, total int
+
+	latest := Select().
+		From("orders").
+		Map("total", nil).
+		Where("customer_id", "= c.id").
+		OrderBy("created").Desc().
+		Limit(3)
+
+	query, args, _ := Select().
+		Dialect(SQLServer).
+		From("jobs").As("j").
+		Map("j.id", &id).
+		Map("o.total", &total).
+		Inner().Join("customers").As("c").On("c.id", "j.customer_id").
+		Left().JoinLateral(latest).As("o").
+		Where("j.state", "= ?", "ready").
+		OrderBy("j.id").
+		Lock().LockOf("j").SkipLocked().
+		Build()
+
+	expectedQuery := `SELECT j.id, o.total
+ FROM jobs AS j WITH (UPDLOCK, ROWLOCK, READPAST)
+ INNER JOIN customers AS c ON c.id = j.customer_id
+ OUTER APPLY (SELECT total
+ FROM orders
+ WHERE (customer_id = c.id)
+ ORDER BY created DESC
+ OFFSET 0 ROWS
+ FETCH NEXT 3 ROWS ONLY) o
+ WHERE (j.state = @p1)
+ ORDER BY j.id`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{"ready"}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestSelectWithWhereOracle(t *testing.T) {
+	c := customer{}
+
+	query, args, _ := Select().
+		Dialect(Oracle).
+		From("customers").As("c").
+		Map("c.id", &c.ID).
+		Map("c.name", &c.Name).
+		Map("c.telephone", &c.Phone).As("phone").
+		Map("c.age", &c.Age).
+		Inner().Join("orders").As("o").On("o.customer_id", "c.id").
+		Where("c.id", "= ?", 9).
+		Where("c.name", "IS NOT NULL").
+		Where("c.age", "BETWEEN ? AND ?", 10, 20).
+		OrderBy("c.name").
+		Limit(5).
+		Offset(10).
+		Build()
+
+	expectedQuery := `SELECT c.id, c.name, c.telephone AS phone, c.age
+ FROM customers c
+ INNER JOIN orders o ON o.customer_id = c.id
+ WHERE (c.id = :1) AND (c.name IS NOT NULL) AND (c.age BETWEEN :2 AND :3)
+ ORDER BY c.name
+ OFFSET 10 ROWS
+ FETCH NEXT 5 ROWS ONLY`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{9, 10, 20}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestSelectPaginationOracle11(t *testing.T) {
+	c := customer{}
+
+	query, _, dest := Select().
+		Dialect(Oracle.Version(11, 2)).
+		From("customers").
+		Map("id", &c.ID).
+		OrderBy("id").
+		Limit(5).
+		Offset(10).
+		Build()
+
+	expectedQuery := `SELECT *
+ FROM (SELECT q.*, ROWNUM rnum_
+ FROM (SELECT id
+ FROM customers
+ ORDER BY id) q
+ WHERE ROWNUM <= 15)
+ WHERE rnum_ > 10`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedDest := []interface{}{&c.ID, &nullDest}
+	if !reflect.DeepEqual(dest, expectedDest) {
+		t.Errorf("bad dest: %v", dest)
+	}
+
+	query, _, _ = Select().
+		Dialect(Oracle.Version(11, 2)).
+		From("customers").
+		Map("id", &c.ID).
+		Limit(5).
+		Build()
+
+	expectedQuery = "SELECT *\n FROM (SELECT id\n FROM customers)\n WHERE ROWNUM <= 5"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+}
+
+func TestSelectSQLite(t *testing.T) {
+	var n int
+
+	query, args, _ := Select().
+		Dialect(SQLite).
+		From("customers").
+		Count(&n).Filter("age > ?", 21).
+		GroupBy("city").
+		OrderBy("city").NullsLast().
+		Offset(10).
+		Lock().
+		Build()
+
+	expectedQuery := `SELECT COUNT(*) FILTER (WHERE age > ?)
+ FROM customers
+ GROUP BY city
+ ORDER BY city NULLS LAST
+ LIMIT -1
+ OFFSET 10`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{21}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestSelectSQLiteRejected(t *testing.T) {
+	cases := []SelectStatement{
+		Select().Dialect(SQLite.RejectLocks()).From("t").Lock(),
+		Select().Dialect(SQLite).From("t").GroupByRollup("a"),
+		Select().Dialect(SQLite).From("t").JoinLateral(Select().From("u")).As("u"),
+	}
+
+	for i, c := range cases {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%d: expected a panic", i)
+				}
+			}()
+			c.Build()
+		}()
+	}
+}
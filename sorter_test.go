@@ -0,0 +1,35 @@
+package sqlbuilder
+
+import "testing"
+
+func TestSorterOrderBy(t *testing.T) {
+	sorter := NewSorter(map[string]string{
+		"created": "o.created_at",
+		"name":    "c.name",
+	})
+
+	s, err := sorter.OrderBy(Select().From("orders").Columns("id"), "-created, +name,")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	query, _, _ := s.OrderBy("id").Build()
+	expectedQuery := "SELECT id\n FROM orders\n ORDER BY o.created_at DESC, c.name, id"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+}
+
+func TestSorterRejectsUnknownKeys(t *testing.T) {
+	sorter := NewSorter(map[string]string{"name": "c.name"})
+
+	for _, spec := range []string{"name,age", "-", "name; DROP TABLE customers"} {
+		s, err := sorter.OrderBy(Select().From("customers"), spec)
+		if err == nil {
+			t.Errorf("%q: expected an error", spec)
+		}
+		if len(s.order) != 0 {
+			t.Errorf("%q: statement was changed", spec)
+		}
+	}
+}
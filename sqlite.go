@@ -0,0 +1,107 @@
+package sqlbuilder
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SQLiteDialect is the dialect for SQLite. By default, it targets the latest release;
+// use Version for earlier releases.
+//
+// SQLite has no row locking, so by default any locking clause is dropped; use RejectLocks
+// to treat it as an error instead.
+type SQLiteDialect struct {
+	version     version
+	schema      string
+	rejectLocks bool
+}
+
+// Version returns the dialect for a particular release of SQLite, e.g. SQLite.Version(3, 35).
+func (dialect SQLiteDialect) Version(v ...int) SQLiteDialect {
+	dialect.version = newVersion(v)
+	return dialect
+}
+
+// RejectLocks returns the dialect with locking clauses treated as an error, which
+// causes Build to panic rather than silently dropping them.
+func (dialect SQLiteDialect) RejectLocks() SQLiteDialect {
+	dialect.rejectLocks = true
+	return dialect
+}
+
+var sqliteFeatures = NewFeatures(FeatureReturning, FeatureUpsert, FeatureReplace, FeatureRightJoin,
+	FeatureFullOuterJoin, FeatureNaturalJoin, FeatureJoinUsing, FeatureWindowFunctions, FeatureFilter,
+	FeatureNullsOrdering, FeatureRowValues, FeatureCTE)
+
+// Schema returns the dialect with a default schema for table names that have none,
+// unless the statement sets its own with Schema.
+func (dialect SQLiteDialect) Schema(schema string) SQLiteDialect {
+	dialect.schema = schema
+	return dialect
+}
+
+func (dialect SQLiteDialect) defaultSchema() string {
+	return dialect.schema
+}
+
+func (dialect SQLiteDialect) Placeholder(idx int) string {
+	return "?"
+}
+
+func (dialect SQLiteDialect) Quote(identifier string) string {
+	return `"` + strings.Replace(identifier, `"`, `""`, -1) + `"`
+}
+
+// Features returns the features of the dialect, which depend on the release: e.g. upsert
+// needs 3.24, window functions need 3.25, RETURNING needs 3.35 and RIGHT and FULL OUTER
+// joins need 3.39.
+func (dialect SQLiteDialect) Features() Features {
+	fs := sqliteFeatures
+	for _, f := range []struct {
+		feature    Feature
+		introduced []int
+	}{
+		{FeatureCTE, []int{3, 8, 3}},
+		{FeatureRowValues, []int{3, 15}},
+		{FeatureUpsert, []int{3, 24}},
+		{FeatureWindowFunctions, []int{3, 25}},
+		{FeatureFilter, []int{3, 30}},
+		{FeatureNullsOrdering, []int{3, 30}},
+		{FeatureReturning, []int{3, 35}},
+		{FeatureRightJoin, []int{3, 39}},
+		{FeatureFullOuterJoin, []int{3, 39}},
+	} {
+		if !dialect.version.atLeast(f.introduced...) {
+			fs = fs.Without(f.feature)
+		}
+	}
+	return fs
+}
+
+// Paginate uses LIMIT and OFFSET. SQLite does not allow OFFSET without LIMIT, so a
+// negative limit, meaning no limit, is used when there is only an offset.
+func (dialect SQLiteDialect) Paginate(query string, p Pagination) string {
+	if p.WithTies {
+		panic("sqlbuilder: WITH TIES is not supported by SQLite")
+	}
+	if p.Limit != nil {
+		query += "\n LIMIT " + strconv.Itoa(*p.Limit)
+	} else if p.Offset != nil {
+		query += "\n LIMIT -1"
+	}
+	if p.Offset != nil {
+		query += "\n OFFSET " + strconv.Itoa(*p.Offset)
+	}
+	return query
+}
+
+func isSQLite(dialect Dialect) bool {
+	_, ok := dialect.(SQLiteDialect)
+	return ok
+}
+
+// dropsLocks is true if the dialect silently drops locking clauses.
+func dropsLocks(dialect Dialect) bool {
+	d, ok := dialect.(SQLiteDialect)
+	return ok && !d.rejectLocks
+}
@@ -0,0 +1,103 @@
+package sqlbuilder
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SQLServerDialect is the dialect for Microsoft SQL Server.
+type SQLServerDialect struct {
+	named  bool
+	schema string
+}
+
+// SQLServer is the SQL Server dialect.
+var SQLServer SQLServerDialect
+
+var sqlServerFeatures = NewFeatures(FeatureReturning, FeatureRightJoin, FeatureFullOuterJoin, FeatureLateral,
+	FeatureWindowFunctions, FeatureRollup, FeatureCube, FeatureGroupingSets, FeatureWithTies,
+	FeatureRowLocking, FeatureLockShare, FeatureNoWait, FeatureSkipLocked, FeatureLockOf, FeatureCTE,
+	FeatureMerge)
+
+// Named returns the dialect with parameters bound by name: placeholders are @name and
+// arguments are sql.NamedArg values. Names are taken from the columns, or from any
+// sql.NamedArg arguments supplied.
+func (dialect SQLServerDialect) Named() SQLServerDialect {
+	dialect.named = true
+	return dialect
+}
+
+func (dialect SQLServerDialect) namePrefix() string {
+	if dialect.named {
+		return "@"
+	}
+	return ""
+}
+
+// Schema returns the dialect with a default schema for table names that have none,
+// unless the statement sets its own with Schema.
+func (dialect SQLServerDialect) Schema(schema string) SQLServerDialect {
+	dialect.schema = schema
+	return dialect
+}
+
+func (dialect SQLServerDialect) defaultSchema() string {
+	return dialect.schema
+}
+
+func (dialect SQLServerDialect) Placeholder(idx int) string {
+	return "@p" + strconv.Itoa(idx+1)
+}
+
+func (dialect SQLServerDialect) Quote(identifier string) string {
+	return "[" + strings.Replace(identifier, "]", "]]", -1) + "]"
+}
+
+func (dialect SQLServerDialect) Features() Features {
+	return sqlServerFeatures
+}
+
+// Paginate uses OFFSET m ROWS FETCH NEXT n ROWS ONLY, which needs an ORDER BY; if there
+// is none, an arbitrary ordering is added. TOP is used instead for a limit without an
+// offset when there is no ORDER BY, and for WITH TIES.
+func (dialect SQLServerDialect) Paginate(query string, p Pagination) string {
+	if p.Offset == nil && (!p.Ordered || p.WithTies) {
+		top := "TOP (" + strconv.Itoa(*p.Limit) + ") "
+		if p.WithTies {
+			if !p.Ordered {
+				panic("sqlbuilder: WITH TIES requires ORDER BY")
+			}
+			top += "WITH TIES "
+		}
+		return insertAfterSelect(query, top)
+	}
+
+	if p.WithTies {
+		panic("sqlbuilder: WITH TIES with an offset is not supported by SQL Server")
+	}
+
+	if !p.Ordered {
+		query += "\n ORDER BY (SELECT NULL)"
+	}
+
+	if p.Offset == nil {
+		zero := 0
+		p.Offset = &zero
+	}
+	return query + fetchFirst(p, "NEXT")
+}
+
+// insertAfterSelect inserts 's' after the SELECT keyword, and after DISTINCT if present.
+func insertAfterSelect(query, s string) string {
+	for _, prefix := range []string{"SELECT DISTINCT ", "SELECT "} {
+		if strings.HasPrefix(query, prefix) {
+			return prefix + s + query[len(prefix):]
+		}
+	}
+	panic("sqlbuilder: not a SELECT query: " + query)
+}
+
+func isSQLServer(dialect Dialect) bool {
+	_, ok := dialect.(SQLServerDialect)
+	return ok
+}
@@ -17,12 +17,15 @@ type updateSet struct {
 
 // UpdateStatement represents an UPDATE statement.
 type UpdateStatement struct {
-	dialect Dialect
-	last    lastWas
-	table   name
-	sets    []updateSet
-	wheres  []where
-	args    []interface{}
+	dialect  Dialect
+	schema   string
+	resolver TableResolver
+	last     lastWas
+	table    name
+	sets     []updateSet
+	wheres   []where
+	args     []interface{}
+	params   params
 }
 
 // Dialect returns a new statement with dialect set to 'dialect'.
@@ -31,9 +34,16 @@ func (s UpdateStatement) Dialect(dialect Dialect) UpdateStatement {
 	return s
 }
 
+// Schema returns a new statement in which table names that have no schema are qualified
+// by 'schema'. This overrides any default schema of the dialect.
+func (s UpdateStatement) Schema(schema string) UpdateStatement {
+	s.schema = schema
+	return s
+}
+
 // Table returns a new statement with the table to update set to 'table'.
 func (s UpdateStatement) Table(table string) UpdateStatement {
-	s.table = name{table, ""}
+	s.table = tableName(table)
 	return s
 }
 
@@ -71,26 +81,27 @@ func (s UpdateStatement) Build() (query string, args []interface{}) {
 		panic("sqlbuilder: UPDATE with no columns set")
 	}
 
-	query = "UPDATE " + s.table.String() + " SET "
+	refs := make(tableRefs)
+	table := refs.resolve(s.table.qualify(s.schema, s.dialect), resolverOrDefault(s.resolver))
+	query = "UPDATE " + table.QuotedAs(s.dialect) + " SET "
 	var sets []string
 	idx := 0
 
 	for _, set := range s.sets {
 		var arg string
 		if set.raw {
-			arg = set.arg.(string)
+			arg = refs.rewrite(set.arg.(string))
 		} else {
-			arg = s.dialect.Placeholder(idx)
-			idx++
-			args = append(args, set.arg)
+			arg, args, idx = bind(s.dialect, args, idx, set.col, set.arg)
 		}
-		sets = append(sets, set.col+" = "+arg)
+		sets = append(sets, refs.rewrite(set.col)+" = "+arg)
 	}
 	query += strings.Join(sets, ", ")
 
 	if len(s.wheres) > 0 {
-		query, args, idx = buildWhereClause(query, args, idx, s.wheres, s.dialect)
+		query, args, idx = buildWhereClause(query, args, idx, refs.conditions(bindParams(s.wheres, s.params)), s.dialect)
 	}
 
+	args = unwrapParams(args)
 	return
 }
@@ -1,6 +1,7 @@
 package sqlbuilder
 
 import (
+	"database/sql"
 	"reflect"
 	"testing"
 )
@@ -43,6 +44,25 @@ func TestUpdatePostgres(t *testing.T) {
 	}
 }
 
+func TestUpdateSQLServer(t *testing.T) {
+	query, args := Update().
+		Dialect(SQLServer).
+		Table("customers").
+		Set("name", "John").
+		Set("phone", "555").
+		Build()
+
+	expectedQuery := `UPDATE customers SET name = @p1, phone = @p2`
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{"John", "555"}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
 func TestUpdateWithWhereMySQL(t *testing.T) {
 	query, args := Update().
 		Dialect(MySQL).
@@ -84,6 +104,81 @@ func TestUpdateWithWherePostgres(t *testing.T) {
 	}
 }
 
+func TestUpdateWithWhereSQLServer(t *testing.T) {
+	query, args := Update().
+		Dialect(SQLServer).
+		Table("customers").
+		Set("name", "John").
+		Set("phone", "555").
+		Where("id", "= ?", 9).
+		Build()
+
+	expectedQuery := "UPDATE customers SET name = @p1, phone = @p2\n WHERE (id = @p3)"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{"John", "555", 9}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestUpdateWithWhereOracle(t *testing.T) {
+	query, args := Update().
+		Dialect(Oracle).
+		Table("customers").
+		Set("name", "John").
+		Set("phone", "555").
+		Where("id", "= ?", 9).
+		Build()
+
+	expectedQuery := "UPDATE customers SET name = :1, phone = :2\n WHERE (id = :3)"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{"John", "555", 9}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestUpdateNamedSQLServer(t *testing.T) {
+	query, args := Update().
+		Dialect(SQLServer.Named()).
+		Table("customers").
+		Set("name", "John").
+		Set("phone", "555").
+		Where("name", "= ?", "Jon").
+		WhereEq("id", 9).
+		Build()
+
+	expectedQuery := "UPDATE customers SET name = @name, phone = @phone\n WHERE (name = @name_2) AND (id =@id)"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+
+	expectedArgs := []interface{}{sql.Named("name", "John"), sql.Named("phone", "555"), sql.Named("name_2", "Jon"), sql.Named("id", 9)}
+	if !reflect.DeepEqual(args, expectedArgs) {
+		t.Errorf("bad args: %v", args)
+	}
+}
+
+func TestUpdateWithSchema(t *testing.T) {
+	query, _ := Update().
+		Dialect(MySQL).
+		Schema("shop").
+		Table("orders").
+		Set("total", 10).
+		Build()
+
+	expectedQuery := "UPDATE shop.orders SET total = ?"
+	if query != expectedQuery {
+		t.Errorf("bad query: %q", query)
+	}
+}
+
 func TestUpdateReuse(t *testing.T) {
 	baseStatement := Update().Dialect(MySQL).Table("customers").Set("name", "John")
 
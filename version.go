@@ -0,0 +1,41 @@
+package sqlbuilder
+
+import (
+	"strconv"
+	"strings"
+)
+
+// version identifies a release of a database server, e.g. 8.0.20. The zero value
+// means the latest release, so that dialects support everything by default.
+type version [3]int
+
+func newVersion(v []int) version {
+	var ver version
+	copy(ver[:], v)
+	return ver
+}
+
+// atLeast is true if the version is the same as or later than 'v'.
+func (ver version) atLeast(v ...int) bool {
+	if ver == (version{}) {
+		return true
+	}
+	other := newVersion(v)
+	for i := range ver {
+		if ver[i] != other[i] {
+			return ver[i] > other[i]
+		}
+	}
+	return true
+}
+
+func (ver version) String() string {
+	if ver == (version{}) {
+		return "latest"
+	}
+	parts := make([]string, len(ver))
+	for i, v := range ver {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, ".")
+}
@@ -10,34 +10,90 @@ type where struct {
 	args     []interface{}
 }
 
-func buildWhereClause(query string, args []interface{}, idx int, wheres []where, dialect Dialect) (string, []interface{}, int) {
-	if len(wheres) > 0 {
-		var sqls []string
+// build renders a single condition, replacing each '?' with the dialect's placeholder
+// and appending the arguments. Slice and array arguments are expanded in place.
+func (w where) build(args []interface{}, idx int, dialect Dialect) (string, []interface{}, int) {
+	sql := w.sql
+	if w.col != "" {
+		sql = w.col + " " + w.sql
+	}
 
-		for _, where := range wheres {
-			sql := "(" + where.col + " " + where.sql + ")"
-			for _, arg := range where.args {
-				value := reflect.ValueOf(arg)
-				switch value.Kind() {
-				case reflect.Array, reflect.Slice:
-					for j := 0; j < value.Len(); j++ {
-						p := dialect.Placeholder(idx)
-						idx++
-						sql = strings.Replace(sql, "?", p, 1)
-						args = append(args, value.Index(j).Interface())
-					}
-
-				default:
-					p := dialect.Placeholder(idx)
-					idx++
-					sql = strings.Replace(sql, "?", p, 1)
-					args = append(args, arg)
-				}
+	replace := func(arg interface{}) {
+		var p string
+		if bp, ok := arg.(boundParam); ok {
+			p, args, idx = bindParam(dialect, args, idx, bp)
+		} else {
+			p, args, idx = bind(dialect, args, idx, w.paramHint(sql), arg)
+		}
+		sql = strings.Replace(sql, "?", p, 1)
+	}
+
+	for _, arg := range w.args {
+		value := reflect.ValueOf(arg)
+		switch value.Kind() {
+		case reflect.Array, reflect.Slice:
+			for j := 0; j < value.Len(); j++ {
+				replace(value.Index(j).Interface())
 			}
-			sqls = append(sqls, sql)
+
+		default:
+			replace(arg)
 		}
+	}
+	return sql, args, idx
+}
 
-		query += "\n WHERE " + strings.Join(sqls, " AND ")
+// paramHint finds a name for the next parameter in 'sql': the column it is compared with,
+// or else the condition's column.
+func (w where) paramHint(sql string) string {
+	i := strings.IndexByte(sql, '?')
+	if i < 0 {
+		return ""
+	}
+	if col := comparedColumn(sql[:i]); col != "" {
+		return col
+	}
+	for _, part := range strings.Split(w.col, ".") {
+		if !isPlainIdentifier(part) {
+			return ""
+		}
+	}
+	return w.col
+}
+
+// markerCount is the number of '?' markers filled by argument 'arg': one for each element
+// of a slice or array, otherwise one.
+func markerCount(arg interface{}) int {
+	value := reflect.ValueOf(arg)
+	switch value.Kind() {
+	case reflect.Array, reflect.Slice:
+		return value.Len()
+	}
+	return 1
+}
+
+// buildConditions renders each condition in turn. The results are not combined.
+func buildConditions(args []interface{}, idx int, conds []where, dialect Dialect) ([]string, []interface{}, int) {
+	var sqls []string
+	for _, cond := range conds {
+		var sql string
+		sql, args, idx = cond.build(args, idx, dialect)
+		sqls = append(sqls, sql)
+	}
+	return sqls, args, idx
+}
+
+func buildWhereClause(query string, args []interface{}, idx int, wheres []where, dialect Dialect) (string, []interface{}, int) {
+	return buildConditionClause(query, "WHERE", args, idx, wheres, dialect)
+}
+
+// buildConditionClause appends a clause such as WHERE or HAVING to the query, combining
+// the conditions with AND.
+func buildConditionClause(query, keyword string, args []interface{}, idx int, conds []where, dialect Dialect) (string, []interface{}, int) {
+	if len(conds) > 0 {
+		var sqls []string
+		sqls, args, idx = buildConditions(args, idx, conds, dialect)
+		query += "\n " + keyword + " (" + strings.Join(sqls, ") AND (") + ")"
 	}
 	return query, args, idx
 }
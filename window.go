@@ -0,0 +1,132 @@
+package sqlbuilder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Window describes the window used by a window function, i.e. the part following OVER.
+// Windows are immutable: each method returns a modified copy.
+type Window struct {
+	ref       string
+	partition []string
+	order     []order
+	frame     string
+}
+
+type namedWindow struct {
+	name   string
+	window Window
+}
+
+// FrameBound is one end of a window frame, e.g. CurrentRow or Preceding(3).
+type FrameBound string
+
+const (
+	UnboundedPreceding FrameBound = "UNBOUNDED PRECEDING" // the first row of the partition
+	CurrentRow         FrameBound = "CURRENT ROW"         // the current row
+	UnboundedFollowing FrameBound = "UNBOUNDED FOLLOWING" // the last row of the partition
+)
+
+// Preceding returns the frame bound 'n' rows before the current row.
+func Preceding(n int) FrameBound {
+	return FrameBound(strconv.Itoa(n) + " PRECEDING")
+}
+
+// Following returns the frame bound 'n' rows after the current row.
+func Following(n int) FrameBound {
+	return FrameBound(strconv.Itoa(n) + " FOLLOWING")
+}
+
+// Over returns a new, empty window. On its own, this spans all the rows of the result.
+func Over() Window {
+	return Window{}
+}
+
+// OverWindow returns a new window based on the named window 'name', which is defined
+// with SelectStatement.Window. Further ordering and framing may be added.
+func OverWindow(name string) Window {
+	return Window{ref: name}
+}
+
+// PartitionBy returns a new window partitioned by the columns 'col'. A window based on a
+// named window cannot be partitioned again, so Validate rejects this after OverWindow.
+// Multiple PartitionBy() calls can be used.
+func (w Window) PartitionBy(col ...string) Window {
+	w.partition = append(w.partition[:len(w.partition):len(w.partition)], col...)
+	return w
+}
+
+// OrderBy returns a new window with ordering 'column', which may be a list of column names.
+// Multiple OrderBy() calls can be used.
+func (w Window) OrderBy(column ...string) Window {
+	w.order = w.order[:len(w.order):len(w.order)]
+	for _, c := range column {
+		w.order = append(w.order, order{col: c})
+	}
+	return w
+}
+
+// Desc reverses the sort order of the last ordering column specified with OrderBy().
+// This panics if there hasn't been an OrderBy yet.
+func (w Window) Desc() Window {
+	i := len(w.order) - 1
+	o := w.order[i]
+	o.desc = true
+	w.order = append(w.order[:i:i], o)
+	return w
+}
+
+// Rows returns a new window with the frame ROWS BETWEEN 'start' AND 'end'.
+// For example Rows(UnboundedPreceding, CurrentRow) gives a running total.
+func (w Window) Rows(start, end FrameBound) Window {
+	w.frame = "ROWS BETWEEN " + string(start) + " AND " + string(end)
+	return w
+}
+
+// Range returns a new window with the frame RANGE BETWEEN 'start' AND 'end'.
+func (w Window) Range(start, end FrameBound) Window {
+	w.frame = "RANGE BETWEEN " + string(start) + " AND " + string(end)
+	return w
+}
+
+// check checks that a window based on a named window adds only ordering and framing.
+func (w Window) check() error {
+	if w.ref != "" && len(w.partition) > 0 {
+		return fmt.Errorf("sqlbuilder: window %s cannot be partitioned again", w.ref)
+	}
+	return nil
+}
+
+// build renders the window specification. A bare reference to a named window is
+// rendered without parentheses.
+func (w Window) build(dialect Dialect) string {
+	if w.ref != "" && len(w.partition) == 0 && len(w.order) == 0 && w.frame == "" {
+		return w.ref
+	}
+	return "(" + w.buildSpec(dialect) + ")"
+}
+
+func (w Window) buildSpec(dialect Dialect) string {
+	var parts []string
+
+	if w.ref != "" {
+		parts = append(parts, w.ref)
+	}
+
+	if len(w.partition) > 0 {
+		parts = append(parts, "PARTITION BY "+strings.Join(w.partition, ", "))
+	}
+
+	if len(w.order) > 0 {
+		orderBy, _, _ := buildOrderBy(nil, 0, w.order, dialect)
+		parts = append(parts, "ORDER BY "+orderBy)
+	}
+
+	if w.frame != "" {
+		parts = append(parts, w.frame)
+	}
+
+	return strings.Join(parts, " ")
+}